@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
-	"os/exec"
+	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -12,39 +16,81 @@ import (
 	"github.com/fatih/color"
 
 	"github.com/carlosarraes/chr/internal/config"
+	"github.com/carlosarraes/chr/internal/conventional"
+	"github.com/carlosarraes/chr/internal/forge"
 	"github.com/carlosarraes/chr/internal/git"
+	"github.com/carlosarraes/chr/internal/jira"
+	"github.com/carlosarraes/chr/internal/logging"
+	"github.com/carlosarraes/chr/internal/marks"
+	"github.com/carlosarraes/chr/internal/notify"
 	"github.com/carlosarraes/chr/internal/picker"
+	"github.com/carlosarraes/chr/internal/provenance"
+	"github.com/carlosarraes/chr/internal/session"
 )
 
 // CLI represents the command-line interface structure
 type CLI struct {
 	// Global flags
-	VersionFlag bool `kong:"short='v',name='version',help='Show version information'"`
-	NoColor     bool `kong:"help='Disable colored output'"`
-	LLM         bool `kong:"help='Show LLM guide for chr usage'"`
+	VersionFlag   bool   `kong:"short='v',name='version',help='Show version information'"`
+	NoColor       bool   `kong:"help='Disable colored output'"`
+	LLM           bool   `kong:"help='Show LLM guide for chr usage'"`
+	Quiet         bool   `kong:"short='q',xor='verbosity',help='Only log errors; results still print to stdout'"`
+	Verbose       bool   `kong:"xor='verbosity',help='Show progress details'"`
+	Debug         bool   `kong:"short='d',xor='verbosity',help='Show debug output'"`
+	LogFile       string `kong:"type='path',help='Also write JSON logs at debug level to this file'"`
+	PrintCommands bool   `kong:"help='Print every git command chr runs to stderr'"`
+
+	logger *slog.Logger
 
 	// Commands
-	Pick    PickCmd    `kong:"cmd,help='Show and cherry-pick commits'"`
-	Config  ConfigCmd  `kong:"cmd,help='Manage configuration'"`
-	Version VersionCmd `kong:"cmd,help='Show version information'"`
+	Pick       PickCmd       `kong:"cmd,help='Show and cherry-pick commits'"`
+	Status     StatusCmd     `kong:"cmd,help='Show the state of the current card branches'"`
+	Notes      NotesCmd      `kong:"cmd,help='Generate markdown release notes'"`
+	Ignore     IgnoreCmd     `kong:"cmd,help='Never list a commit as unpicked, or --list marked commits'"`
+	MarkPicked MarkPickedCmd `kong:"cmd,help='Record a commit as already picked'"`
+	Config     ConfigCmd     `kong:"cmd,help='Manage configuration'"`
+	Version    VersionCmd    `kong:"cmd,help='Show version information'"`
 }
 
 type PickCmd struct {
-	Count       int    `kong:"short='c',default='5',help='Number of commits to pick'"`
-	Latest      bool   `kong:"short='l',help='Pick latest commits from current user (up to 100)'"`
-	Show        bool   `kong:"short='s',help='Show commits instead of picking (dry run)'"`
-	Today       bool   `kong:"help='Show commits from today only'"`
-	Yesterday   bool   `kong:"help='Show commits from yesterday only'"`
-	Since       string `kong:"help='Show commits since date (YYYY-MM-DD)'"`
-	Until       string `kong:"help='Show commits until date (YYYY-MM-DD)'"`
-	Interactive bool   `kong:"short='i',help='Interactive commit selection'"`
-	Continue    bool   `kong:"help='Continue cherry-picking after resolving conflicts'"`
-	Debug       bool   `kong:"short='d',help='Show debug output'"`
-	NoFilter    bool   `kong:"help='Disable smart filtering - show latest N commits without deduplication'"`
-	Reverse     bool   `kong:"short='r',help='Reverse direction: pick from HML to PRD instead of PRD to HML'"`
-	Prefix      string `kong:"help='Override branch prefix (e.g., ZUP-)'"`
-	SuffixPrd   string `kong:"help='Override production branch suffix (e.g., -prd)'"`
-	SuffixHml   string `kong:"help='Override homologation branch suffix (e.g., -hml)'"`
+	Count          int      `kong:"short='c',default='5',help='Number of commits to pick'"`
+	Latest         bool     `kong:"short='l',help='Pick latest commits from current user (up to 100)'"`
+	Show           bool     `kong:"short='s',help='Show commits instead of picking (dry run)'"`
+	Today          bool     `kong:"help='Show commits from today only'"`
+	Yesterday      bool     `kong:"help='Show commits from yesterday only'"`
+	Since          string   `kong:"help='Show commits since date (YYYY-MM-DD)'"`
+	Until          string   `kong:"help='Show commits until date (YYYY-MM-DD)'"`
+	Interactive    bool     `kong:"short='i',help='Interactive commit selection'"`
+	Continue       bool     `kong:"xor='resume',help='Continue cherry-picking after resolving conflicts or an interruption'"`
+	Abort          bool     `kong:"xor='resume',help='Undo an unfinished pick, resetting the branch to where it started'"`
+	NoFilter       bool     `kong:"help='Disable smart filtering - show latest N commits without deduplication'"`
+	Reverse        bool     `kong:"short='r',help='Reverse direction: pick from HML to PRD instead of PRD to HML'"`
+	Prefix         string   `kong:"help='Override branch prefix (e.g., ZUP-)'"`
+	SuffixPrd      string   `kong:"help='Override production branch suffix (e.g., -prd)'"`
+	SuffixHml      string   `kong:"help='Override homologation branch suffix (e.g., -hml)'"`
+	Fetch          bool     `kong:"help='Always fetch the card branches from the remote before comparing'"`
+	NoFetch        bool     `kong:"help='Never fetch, even when a card branch only exists on the remote'"`
+	Remote         bool     `kong:"help='Compare using remote-tracking refs only, ignoring local branches'"`
+	Push           bool     `kong:"help='Push the target branch to the remote after a successful pick'"`
+	ForceWithLease bool     `kong:"help='Use --force-with-lease when pushing'"`
+	Type           []string `kong:"help='Only pick commits of these Conventional Commits types (e.g., feat,fix)'"`
+	Scope          []string `kong:"help='Only pick commits with these scopes (e.g., api)'"`
+	ExcludeType    []string `kong:"help='Skip commits of these types (e.g., chore)'"`
+	Ticket         []string `kong:"help='Pick every unpicked commit referencing these tickets (e.g., ZUP-456,ZUP-789), regardless of author or date'"`
+	Grep           string   `kong:"help='Only pick commits whose message matches this regular expression'"`
+	Path           []string `kong:"help='Only pick commits touching these paths (globs, e.g. services/billing/**)'"`
+	ExcludePath    []string `kong:"help='Ignore changes under these paths when selecting commits'"`
+	GroupBy        string   `kong:"enum=',type,author,date',default='',help='Group --show output by type, author or date'"`
+	Format         string   `kong:"enum='text,json',default='text',help='Output format for --show (text or json)'"`
+	Report         string   `kong:"help='Write the pick report to a file (JSON for .json, markdown otherwise)'"`
+	PR             bool     `kong:"name='pr',help='Pick onto a new branch from the target, push it and open a pull/merge request'"`
+	Jira           bool     `kong:"help='Comment on the card Jira issue after a successful pick'"`
+	Merges         string   `kong:"enum=',skip,mainline,expand',default='',help='Merge commits: skip, mainline (pick with -m) or expand into their commits (default: merge_policy)'"`
+	Mainline       int      `kong:"help='Parent number merges are picked relative to with --merges mainline (default: merge_mainline)'"`
+	Empty          string   `kong:"enum=',skip,keep,stop',default='',help='Commits already applied on the target: skip, keep as empty commits or stop (default: empty_policy)'"`
+	Match          string   `kong:"help='Matching strategies in priority order, e.g. exact,patch_id (default: match_strategies)'"`
+
+	log *slog.Logger
 }
 
 // ConfigCmd represents the config subcommand
@@ -56,9 +102,41 @@ type ConfigCmd struct {
 
 type VersionCmd struct{}
 
-func (p *PickCmd) Run(ctx *kong.Context, globals *CLI) error {
+func (p *PickCmd) Run(ctx context.Context, globals *CLI) error {
+	p.log = globals.logger
+
 	if p.Continue {
-		return handleContinue()
+		return p.handleContinue(ctx)
+	}
+	if p.Abort {
+		return p.handleAbort(ctx)
+	}
+
+	if p.Fetch && p.NoFetch {
+		return fmt.Errorf("--fetch and --no-fetch cannot be used together")
+	}
+
+	var grepPattern *regexp.Regexp
+	if p.Grep != "" {
+		pattern, err := regexp.Compile(p.Grep)
+		if err != nil {
+			return fmt.Errorf("invalid --grep pattern: %w", err)
+		}
+		grepPattern = pattern
+	}
+
+	if len(p.Ticket) > 0 && (p.Today || p.Yesterday || p.Since != "" || p.Until != "" || p.Latest) {
+		return fmt.Errorf("--ticket selects commits regardless of author or date and cannot be combined with date filters or --latest")
+	}
+
+	if p.GroupBy != "" && !p.Show {
+		return fmt.Errorf("--group-by can only be used with --show")
+	}
+	if p.Format == formatJSON && !p.Show {
+		return fmt.Errorf("--format json can only be used with --show")
+	}
+	if p.PR && (p.Push || p.ForceWithLease) {
+		return fmt.Errorf("--pr pushes its own pick branch and cannot be combined with --push or --force-with-lease")
 	}
 
 	// Load configuration first
@@ -66,6 +144,10 @@ func (p *PickCmd) Run(ctx *kong.Context, globals *CLI) error {
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
+	ctx, err = useBackend(ctx, cfg)
+	if err != nil {
+		return err
+	}
 
 	if p.Prefix != "" {
 		cfg.Prefix = p.Prefix
@@ -77,6 +159,33 @@ func (p *PickCmd) Run(ctx *kong.Context, globals *CLI) error {
 		cfg.SuffixHml = p.SuffixHml
 	}
 
+	if p.Merges != "" {
+		cfg.MergePolicy = p.Merges
+	}
+	if p.Mainline > 0 {
+		cfg.MergeMainline = p.Mainline
+	}
+	if p.Empty != "" {
+		cfg.EmptyPolicy = p.Empty
+	}
+	if err := ValidateConfigValue("merge_policy", cfg.MergePolicy); err != nil {
+		return err
+	}
+	if err := ValidateConfigValue("empty_policy", cfg.EmptyPolicy); err != nil {
+		return err
+	}
+	if p.Match != "" {
+		strategies, err := picker.ParseStrategies(p.Match)
+		if err != nil {
+			return err
+		}
+		cfg.MatchStrategies = strategies
+	}
+	matchOpts, err := matchOptions(cfg)
+	if err != nil {
+		return err
+	}
+
 	// Setup colors - global flag overrides config
 	color.NoColor = globals.NoColor || !cfg.Color
 
@@ -86,185 +195,781 @@ func (p *PickCmd) Run(ctx *kong.Context, globals *CLI) error {
 		return fmt.Errorf("failed to get current directory: %w", err)
 	}
 
-	// Get current branch
-	currentBranch, err := git.GetCurrentBranch(repoDir)
+	card, err := resolveCardBranches(ctx, repoDir, cfg)
 	if err != nil {
-		return fmt.Errorf("failed to get current branch: %w", err)
+		return err
 	}
+	currentBranch, prdBranch, hmlBranch := card.Current, card.Prd, card.Hml
 
-	var currentSuffix string
-	if strings.HasSuffix(currentBranch, cfg.SuffixPrd) {
-		currentSuffix = cfg.SuffixPrd
-	} else if strings.HasSuffix(currentBranch, cfg.SuffixHml) {
-		currentSuffix = cfg.SuffixHml
-	} else {
-		return fmt.Errorf("current branch '%s' doesn't end with PRD suffix '%s' or HML suffix '%s'", currentBranch, cfg.SuffixPrd, cfg.SuffixHml)
+	if p.shouldFetch(ctx, repoDir, prdBranch, hmlBranch) {
+		fetch := []string{prdBranch, hmlBranch}
+		if p.Remote {
+			// main bounds the target commits read for filtering, so it must be current too
+			fetch = append(fetch, "main")
+		}
+		if err := git.FetchBranches(ctx, repoDir, cfg.Remote, p.log, fetch...); err != nil {
+			return fmt.Errorf("failed to fetch branches: %w", err)
+		}
 	}
 
-	branchIdentifier, err := git.ParseBranchName(currentBranch, cfg.Prefix, currentSuffix)
-	if err != nil {
-		return fmt.Errorf("failed to parse branch name: %w", err)
+	if err := p.checkBranchExists(ctx, repoDir, cfg.Remote, prdBranch); err != nil {
+		return fmt.Errorf("PRD %w", err)
 	}
 
-	prdBranch := cfg.Prefix + branchIdentifier + cfg.SuffixPrd
-	hmlBranch := cfg.Prefix + branchIdentifier + cfg.SuffixHml
-
-	if exists, err := git.BranchExists(repoDir, prdBranch); err != nil {
-		return fmt.Errorf("failed to check PRD branch: %w", err)
-	} else if !exists {
-		return fmt.Errorf("PRD branch '%s' does not exist", prdBranch)
+	if err := p.checkBranchExists(ctx, repoDir, cfg.Remote, hmlBranch); err != nil {
+		return fmt.Errorf("HML %w", err)
 	}
 
-	if exists, err := git.BranchExists(repoDir, hmlBranch); err != nil {
-		return fmt.Errorf("failed to check HML branch: %w", err)
-	} else if !exists {
-		return fmt.Errorf("HML branch '%s' does not exist", hmlBranch)
+	if err := p.checkSync(ctx, repoDir, cfg.Remote, prdBranch, hmlBranch); err != nil {
+		return err
 	}
 
 	var sourceBranch, targetBranch string
 	if p.Reverse {
 		sourceBranch = hmlBranch
 		targetBranch = prdBranch
-		fmt.Printf("Current branch: %s\n", currentBranch)
-		fmt.Printf("Source (HML) branch: %s\n", sourceBranch)
-		fmt.Printf("Target (PRD) branch: %s\n", targetBranch)
+		p.infof("Current branch: %s\n", currentBranch)
+		p.infof("Source (HML) branch: %s\n", sourceBranch)
+		p.infof("Target (PRD) branch: %s\n", targetBranch)
 	} else {
 		sourceBranch = prdBranch
 		targetBranch = hmlBranch
-		fmt.Printf("Current branch: %s\n", currentBranch)
-		fmt.Printf("Source (PRD) branch: %s\n", sourceBranch)
-		fmt.Printf("Target (HML) branch: %s\n", targetBranch)
+		p.infof("Current branch: %s\n", currentBranch)
+		p.infof("Source (PRD) branch: %s\n", sourceBranch)
+		p.infof("Target (HML) branch: %s\n", targetBranch)
 	}
 
 	commitLimit := 100
 	if p.Latest {
 		commitLimit = 20
-	} else if p.Show {
+	} else if p.Show || len(p.Ticket) > 0 {
 		commitLimit = 0
 	}
 
-	sourceCommits, err := git.GetCommits(repoDir, targetBranch, sourceBranch, commitLimit, p.Debug)
+	sourceRef := p.resolveRef(ctx, repoDir, cfg.Remote, sourceBranch)
+	targetRef := p.resolveRef(ctx, repoDir, cfg.Remote, targetBranch)
+
+	sourceCommits, err := git.GetCommits(ctx, repoDir, targetRef, sourceRef, commitLimit, p.log)
 	if err != nil {
 		return fmt.Errorf("failed to get source commits: %w", err)
 	}
 
 	if len(sourceCommits) == 0 {
-		fmt.Printf("No new commits found in %s branch.\n", sourceBranch)
-		return nil
+		return p.noCommits(fmt.Sprintf("No new commits found in %s branch.", sourceBranch), sourceBranch, targetBranch)
+	}
+
+	allSourceCommits := sourceCommits
+	sourceCommits, skippedMerges, err := applyMergePolicy(ctx, repoDir, cfg.MergePolicy, sourceCommits, targetRef, sourceRef)
+	if err != nil {
+		return err
 	}
 
 	// Get current user for filtering
-	currentUser, err := git.GetCurrentUser(repoDir)
+	currentUser, err := git.GetCurrentUser(ctx, repoDir)
 	if err != nil {
 		return fmt.Errorf("failed to get current user: %w", err)
 	}
 
-	userCommits := git.FilterCommitsByAuthor(sourceCommits, currentUser)
+	var filteredCommits []git.Commit
+	if len(p.Ticket) > 0 {
+		ticketPattern, err := cfg.TicketRegexp()
+		if err != nil {
+			return err
+		}
+		filteredCommits = picker.FilterCommitsByTicket(sourceCommits, ticketPattern, p.Ticket)
+	} else {
+		userCommits := git.FilterCommitsByAuthor(sourceCommits, currentUser)
 	
-	if p.Debug {
-		fmt.Printf("Debug: Found %d commits from user %s in %s:\n", len(userCommits), currentUser, sourceBranch)
-		for i, commit := range userCommits {
-			fmt.Printf("  %d. %s - %s - %s\n", i+1, commit.Hash, commit.Date, commit.Message)
+		p.log.Debug("found commits from user", "count", len(userCommits), "user", currentUser, "branch", sourceBranch)
+		for _, commit := range userCommits {
+			p.log.Debug("user commit", "hash", commit.Hash, "date", commit.Date, "subject", commit.Message)
+		}
+
+		// Apply date filtering
+		if p.Today {
+			filteredCommits = git.FilterCommitsByDate(userCommits, git.NewTodayFilter())
+		} else if p.Yesterday {
+			filteredCommits = git.FilterCommitsByDate(userCommits, git.NewYesterdayFilter())
+		} else if p.Since != "" {
+			if err := validateDate(p.Since); err != nil {
+				return fmt.Errorf("invalid since date: %w", err)
+			}
+			since, _ := time.Parse("2006-01-02", p.Since)
+			filter := &git.DateFilter{Type: git.DateFilterTypeSince, Since: since}
+			filteredCommits = git.FilterCommitsByDate(userCommits, filter)
+		} else if p.Until != "" {
+			if err := validateDate(p.Until); err != nil {
+				return fmt.Errorf("invalid until date: %w", err)
+			}
+			until, _ := time.Parse("2006-01-02", p.Until)
+			filter := &git.DateFilter{Type: git.DateFilterTypeUntil, Until: until}
+			filteredCommits = git.FilterCommitsByDate(userCommits, filter)
+		} else {
+			filteredCommits = userCommits
 		}
 	}
 
-	// Apply date filtering
-	var filteredCommits []git.Commit
-	if p.Today {
-		filteredCommits = git.FilterCommitsByDate(userCommits, git.NewTodayFilter())
-	} else if p.Yesterday {
-		filteredCommits = git.FilterCommitsByDate(userCommits, git.NewYesterdayFilter())
-	} else if p.Since != "" {
-		if err := validateDate(p.Since); err != nil {
-			return fmt.Errorf("invalid since date: %w", err)
-		}
-		since, _ := time.Parse("2006-01-02", p.Since)
-		filter := &git.DateFilter{Type: git.DateFilterTypeSince, Since: since}
-		filteredCommits = git.FilterCommitsByDate(userCommits, filter)
-	} else if p.Until != "" {
-		if err := validateDate(p.Until); err != nil {
-			return fmt.Errorf("invalid until date: %w", err)
-		}
-		until, _ := time.Parse("2006-01-02", p.Until)
-		filter := &git.DateFilter{Type: git.DateFilterTypeUntil, Until: until}
-		filteredCommits = git.FilterCommitsByDate(userCommits, filter)
-	} else {
-		filteredCommits = userCommits
+	// The merges selected above stand for the commits they bring, which the
+	// type, message and path filters below then apply to
+	if cfg.MergePolicy == picker.MergePolicyExpand {
+		filteredCommits, err = expandMerges(ctx, repoDir, filteredCommits, allSourceCommits)
+		if err != nil {
+			return err
+		}
+	}
+
+	filteredCommits = picker.FilterCommitsByType(filteredCommits, picker.TypeFilter{
+		Types:        p.Type,
+		Scopes:       p.Scope,
+		ExcludeTypes: p.ExcludeType,
+	})
+	filteredCommits = git.FilterCommitsByMessage(filteredCommits, grepPattern)
+
+	if len(p.Path) > 0 || len(p.ExcludePath) > 0 {
+		if err := git.LoadChangedFiles(ctx, repoDir, filteredCommits); err != nil {
+			return err
+		}
+		filteredCommits = git.FilterCommitsByPath(filteredCommits, p.Path, p.ExcludePath)
 	}
 
 	if len(filteredCommits) == 0 {
-		fmt.Println("No commits found for the current user with the specified filters.")
-		return nil
+		if len(p.Ticket) > 0 {
+			return p.noCommits(fmt.Sprintf("No commits found referencing %s.", strings.Join(p.Ticket, ", ")), sourceBranch, targetBranch)
+		}
+		return p.noCommits("No commits found for the current user with the specified filters.", sourceBranch, targetBranch)
 	}
 
 	var unpickedCommits []git.Commit
 	if p.NoFilter {
-		unpickedCommits = filteredCommits
-		if p.Debug {
-			fmt.Printf("Debug: Using --no-filter, skipping smart deduplication\n")
+		// Ignored commits are never to be picked, deduplicated or not
+		all, err := marks.Load(ctx, repoDir)
+		if err != nil {
+			return err
 		}
+		unpickedCommits = picker.ExcludeIgnored(filteredCommits, marks.NewSet(all))
+		p.log.Debug("using --no-filter, skipping smart deduplication")
 	} else {
-		targetCommits, err := git.GetCommits(repoDir, "main", targetBranch, 100, p.Debug)
+		targetCommits, err := git.GetCommits(ctx, repoDir, p.resolveRef(ctx, repoDir, cfg.Remote, "main"), targetRef, 100, p.log)
 		if err != nil {
 			return fmt.Errorf("failed to get target commits: %w", err)
 		}
 
-		unpickedCommits = picker.FilterUnpickedCommits(filteredCommits, targetCommits, p.Debug)
+		unpickedCommits, err = filterUnpicked(ctx, repoDir, matchOpts, filteredCommits, targetCommits, p.log)
+		if err != nil {
+			return err
+		}
+		unpickedCommits = excludeAlreadyApplied(ctx, repoDir, unpickedCommits, sourceBranch, targetBranch)
 	}
 
 	if len(unpickedCommits) == 0 {
-		fmt.Printf("All commits have already been picked to %s branch.\n", targetBranch)
-		return nil
+		return p.noCommits(fmt.Sprintf("All commits have already been picked to %s branch.", targetBranch), sourceBranch, targetBranch)
 	}
 
-	if !p.Show {
-		if !p.Latest && p.Count != 5 {
-			if len(unpickedCommits) > p.Count {
-				unpickedCommits = unpickedCommits[:p.Count]
+	// --ticket selects every matching commit, so --count doesn't apply
+	if len(p.Ticket) == 0 {
+		if !p.Show {
+			if !p.Latest && p.Count != 5 {
+				if len(unpickedCommits) > p.Count {
+					unpickedCommits = unpickedCommits[:p.Count]
+				}
+			} else if !p.Latest {
+				if len(unpickedCommits) > p.Count {
+					unpickedCommits = unpickedCommits[:p.Count]
+				}
 			}
-		} else if !p.Latest {
-			if len(unpickedCommits) > p.Count {
-				unpickedCommits = unpickedCommits[:p.Count]
-			}
-		}
-	} else {
-		if p.Count != 5 {
-			if len(unpickedCommits) > p.Count {
-				unpickedCommits = unpickedCommits[:p.Count]
+		} else {
+			if p.Count != 5 {
+				if len(unpickedCommits) > p.Count {
+					unpickedCommits = unpickedCommits[:p.Count]
+				}
 			}
 		}
 	}
 
 	// Display commits
-	fmt.Printf("\nFound %d unpicked commits:\n", len(unpickedCommits))
-	for i, commit := range unpickedCommits {
-		displayCommit(i+1, commit, currentUser, cfg.Color)
+	listing := newCommitListing(sourceBranch, targetBranch, p.GroupBy, unpickedCommits)
+	listing.MergePolicy = cfg.MergePolicy
+	listing.SkippedMerges = skippedMerges
+	if cfg.MergePolicy == picker.MergePolicyMainline {
+		listing.Mainline = cfg.MergeMainline
+	}
+	if p.Format == formatJSON {
+		return writeListingJSON(os.Stdout, listing)
 	}
+	displayListing(listing, currentUser, cfg.Color)
 
 	if p.Show {
 		fmt.Println("\nDry-run mode. Remove --show to actually cherry-pick these commits.")
 		return nil
 	}
 
-	// Cherry-pick mode
+	// Cherry-pick mode. Commits land on HEAD, so it must be the target branch
+	// pushed and recorded below; --pr checks out its own branch from the target
+	if !p.PR && currentBranch != targetBranch {
+		return fmt.Errorf("commits are picked onto the checked-out branch, but %s is checked out; switch to %s first", currentBranch, targetBranch)
+	}
+	if err := git.CheckCleanTree(ctx, repoDir); err != nil {
+		return err
+	}
 
-	// Extract commit hashes
-	var commitHashes []string
-	for _, commit := range unpickedCommits {
-		commitHashes = append(commitHashes, commit.Hash)
+	// Oldest first so they apply in history order
+	var pickOrder []git.Commit
+	for i := len(unpickedCommits) - 1; i >= 0; i-- {
+		pickOrder = append(pickOrder, unpickedCommits[i])
 	}
 
+	startedAt := time.Now()
+	pickSession := session.Session{
+		ID:           session.NewID(startedAt),
+		StartedAt:    startedAt,
+		User:         currentUser,
+		Card:         card.Card,
+		SourceBranch: sourceBranch,
+		TargetBranch: targetBranch,
+		Commits:      unpickedCommits,
+		Mainline:     cfg.MergeMainline,
+		EmptyPolicy:  cfg.EmptyPolicy,
+		Outcome:      session.OutcomeSuccess,
+
+		Push:           p.Push || cfg.PushAfterPick,
+		ForceWithLease: p.ForceWithLease,
+		JiraComment:    p.Jira || cfg.JiraComment,
+	}
+	if p.Report != "" {
+		// --continue may run from another directory of the repository
+		if pickSession.Report, err = filepath.Abs(p.Report); err != nil {
+			return fmt.Errorf("invalid --report path: %w", err)
+		}
+	}
+
+	if p.PR {
+		// Fail on forge settings before touching any branch
+		if _, err := newForgeClient(ctx, repoDir, cfg); err != nil {
+			return err
+		}
+
+		pickSession.PickBranch = fmt.Sprintf("%s-pick-%s", targetBranch, pickSession.ID)
+		if err := git.CreateBranch(ctx, repoDir, pickSession.PickBranch, targetRef); err != nil {
+			return err
+		}
+		fmt.Printf("Created %s from %s\n", pickSession.PickBranch, targetRef)
+	}
+
+	// Where chr pick --abort takes the branch back to
+	startHead, err := git.GetCommitHash(ctx, repoDir, "HEAD")
+	if err != nil {
+		return err
+	}
+	pickSession.StartHead = startHead
+
 	// Perform cherry-pick
-	if err := git.CherryPickCommits(repoDir, commitHashes); err != nil {
-		return fmt.Errorf("cherry-pick failed: %w", err)
+	results, pickErr := git.CherryPickCommits(ctx, repoDir, pickOrder, git.PickOptions{Mainline: cfg.MergeMainline, Empty: cfg.EmptyPolicy})
+	pickSession.Picks = results
+
+	return p.finishSession(ctx, repoDir, cfg, pickSession, pickErr)
+}
+
+// finishSession records a pick session, reports it and pushes the target when asked.
+// What to do once the pick finishes (--push, --report, --jira) is read from the
+// session, so a pick resumed with --continue keeps the flags it started with.
+// pickErr is the ConflictError, AlreadyAppliedError or InterruptedError the pick
+// stopped on, or any other error it failed with, and is returned once the
+// session is recorded.
+//
+// The commits are picked by the time it runs, so the post-pick hooks (session
+// log, provenance notes, notifications, Jira) only warn when they fail.
+func (p *PickCmd) finishSession(ctx context.Context, repoDir string, cfg *config.Config, pickSession session.Session, pickErr error) error {
+	var conflict *git.ConflictError
+	var interrupted *git.InterruptedError
+	var applied *git.AlreadyAppliedError
+	switch {
+	case errors.As(pickErr, &conflict):
+		pickSession.Outcome = session.OutcomeConflict
+	case errors.As(pickErr, &interrupted):
+		pickSession.Outcome = session.OutcomeInterrupted
+	case errors.As(pickErr, &applied):
+		pickSession.Outcome = session.OutcomeStopped
+	case pickErr != nil:
+		pickSession.Outcome = session.OutcomeFailed
+		pickErr = fmt.Errorf("cherry-pick failed: %w", pickErr)
+	default:
+		pickSession.Outcome = session.OutcomeSuccess
+	}
+	// Recorded even after an interruption, so --continue and --abort can find it
+	recordSession(context.WithoutCancel(ctx), p.log, repoDir, pickSession)
+	recordProvenance(context.WithoutCancel(ctx), p.log, repoDir, pickSession)
+
+	report := session.NewReport(pickSession)
+	printReport(report)
+	if pickSession.Report != "" {
+		if err := session.WriteReport(pickSession.Report, report); err != nil {
+			return err
+		}
+		fmt.Printf("Report written to %s\n", pickSession.Report)
 	}
 
-	fmt.Println("Successfully cherry-picked all commits!")
+	event := notify.EventPick
+	switch pickSession.Outcome {
+	case session.OutcomeConflict:
+		event = notify.EventConflict
+	case session.OutcomeStopped:
+		event = notify.EventStopped
+	case session.OutcomeInterrupted:
+		event = notify.EventInterrupted
+	case session.OutcomeFailed:
+		event = notify.EventFailed
+	}
+	sendNotifications(p.log, cfg, event, pickSession.Card, report)
+
+	if pickSession.Outcome != session.OutcomeSuccess {
+		if pickSession.Outcome == session.OutcomeInterrupted {
+			fmt.Printf("\nInterrupted: %d commits left to pick. Nothing is in progress on %s.\n", interrupted.Pending, sessionBranch(pickSession))
+			fmt.Println("\nWhat to do:")
+			fmt.Println("1. Pick the rest: chr pick --continue")
+			fmt.Println("2. Or undo the whole pick: chr pick --abort")
+		}
+		if pickSession.Outcome == session.OutcomeFailed {
+			fmt.Printf("\nThe pick failed with %d commits left to pick on %s.\n", report.Count(git.PickStatusPending), sessionBranch(pickSession))
+			fmt.Println("\nWhat to do:")
+			fmt.Println("1. Fix the cause and pick the rest: chr pick --continue")
+			fmt.Println("2. Or undo the whole pick: chr pick --abort")
+		}
+		if pickSession.PickBranch != "" {
+			fmt.Println("Pull request not opened yet: finish the pick with chr pick --continue.")
+		} else if pickSession.Push {
+			fmt.Println("Skipping push: the pick has not finished.")
+		}
+		return pickErr
+	}
+
+	if pickSession.PickBranch != "" {
+		if err := openPullRequest(ctx, repoDir, cfg, pickSession, report); err != nil {
+			return err
+		}
+	} else if pickSession.Push {
+		if err := pushTarget(ctx, repoDir, cfg.Remote, pickSession.TargetBranch, pickSession.ForceWithLease); err != nil {
+			return err
+		}
+	}
+
+	// A pick that only skipped commits changed nothing worth telling Jira about
+	if (pickSession.JiraComment || cfg.JiraTransition != "") && report.Count(git.PickStatusPicked) > 0 {
+		updateJira(p.log, cfg, pickSession, report, pickSession.JiraComment)
+	}
+
+	if picked := report.Count(git.PickStatusPicked); picked > 0 {
+		fmt.Printf("Successfully cherry-picked: %d picked, %d skipped.\n", picked, len(report.Entries)-picked)
+	}
+	return nil
+}
+
+// sendNotifications posts the session to the configured webhooks, retrying
+// each one as set in the [notify] config
+func sendNotifications(log *slog.Logger, cfg *config.Config, event, card string, report session.Report) {
+	if len(cfg.Notify.Webhooks) == 0 {
+		return
+	}
+
+	notifier := notify.New(cfg.Notify.Webhooks, time.Duration(cfg.Notify.TimeoutSeconds)*time.Second, cfg.Notify.Retries)
+	for _, err := range notifier.Send(notify.NewPayload(event, card, report)) {
+		log.Warn("notification failed", "err", err)
+	}
+}
+
+// updateJira comments on and/or transitions the card's Jira issue
+func updateJira(log *slog.Logger, cfg *config.Config, s session.Session, report session.Report, comment bool) {
+	issueKey := cfg.Prefix + s.Card
+
+	client, err := jira.NewClient(cfg.JiraURL, cfg.JiraUser, cfg.JiraToken)
+	if err != nil {
+		log.Warn("skipping Jira update", "issue", issueKey, "err", err)
+		return
+	}
+
+	if comment {
+		if err := client.AddComment(issueKey, jiraComment(report)); err != nil {
+			log.Warn("Jira comment failed", "issue", issueKey, "err", err)
+		} else {
+			fmt.Printf("✓ Commented on %s\n", issueKey)
+		}
+	}
+
+	if cfg.JiraTransition != "" {
+		if err := client.TransitionTo(issueKey, cfg.JiraTransition); err != nil {
+			log.Warn("Jira transition failed", "issue", issueKey, "status", cfg.JiraTransition, "err", err)
+		} else {
+			fmt.Printf("✓ Moved %s to %s\n", issueKey, cfg.JiraTransition)
+		}
+	}
+}
+
+// jiraComment lists the commits picked in a session as a plain-text Jira comment
+func jiraComment(report session.Report) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Cherry-picked into %s from %s by %s (%s):\n", report.TargetBranch, report.SourceBranch, report.User, report.Summary())
+	for _, entry := range report.Entries {
+		if entry.Status != git.PickStatusPicked {
+			continue
+		}
+		fmt.Fprintf(&b, "- %s %s (%s)\n", session.ShortHash(entry.Target), entry.Subject, entry.Author)
+	}
+	return b.String()
+}
+
+// printReport shows where each source commit ended up on the target branch
+func printReport(report session.Report) {
+	fmt.Printf("\nPick report (%s):\n", report.Summary())
+	for _, entry := range report.Entries {
+		switch entry.Status {
+		case git.PickStatusPicked:
+			fmt.Printf("  %s → %s  %s\n", entry.Source, session.ShortHash(entry.Target), entry.Subject)
+		default:
+			if entry.Target != "" {
+				fmt.Printf("  %s → %s (%s)  %s\n", entry.Source, session.ShortHash(entry.Target), entry.Status.Label(), entry.Subject)
+			} else {
+				fmt.Printf("  %s (%s)  %s\n", entry.Source, entry.Status.Label(), entry.Subject)
+			}
+		}
+	}
+}
+
+// applyMergePolicy applies a merge policy to the source commits between targetRef
+// and sourceRef: "skip" drops the merges and returns how many, "mainline" keeps
+// the first-parent history only. "expand" is left to expandMerges, which needs
+// the commits selected first.
+func applyMergePolicy(ctx context.Context, repoDir, policy string, commits []git.Commit, targetRef, sourceRef string) ([]git.Commit, int, error) {
+	switch policy {
+	case picker.MergePolicySkip:
+		commits, skipped := picker.SkipMerges(commits)
+		return commits, skipped, nil
+	case picker.MergePolicyMainline:
+		firstParent, err := git.GetFirstParentHashes(ctx, repoDir, targetRef, sourceRef)
+		if err != nil {
+			return nil, 0, err
+		}
+		return picker.FilterFirstParent(commits, firstParent), 0, nil
+	}
+	return commits, 0, nil
+}
+
+// expandMerges replaces the selected merges with the commits they brought in
+func expandMerges(ctx context.Context, repoDir string, selected, all []git.Commit) ([]git.Commit, error) {
+	merged := make(map[string][]string)
+	for _, commit := range selected {
+		if !commit.IsMerge() {
+			continue
+		}
+		hashes, err := git.GetMergedCommits(ctx, repoDir, commit.Hash)
+		if err != nil {
+			return nil, err
+		}
+		merged[commit.Hash] = hashes
+	}
+	return picker.ExpandMerges(selected, all, merged), nil
+}
+
+// matchOptions builds the commit matcher settings from the config
+func matchOptions(cfg *config.Config) (picker.MatchOptions, error) {
+	strategies, err := picker.ParseStrategies(strings.Join(cfg.MatchStrategies, ","))
+	if err != nil {
+		return picker.MatchOptions{}, err
+	}
+	if err := ValidateConfigValue("match_min_score", strconv.Itoa(cfg.MatchMinScore)); err != nil {
+		return picker.MatchOptions{}, err
+	}
+	return picker.MatchOptions{Strategies: strategies, MinScore: cfg.MatchMinScore}, nil
+}
+
+// filterUnpicked drops the source commits already on the target or marked with
+// chr ignore or chr mark-picked. It loads the provenance notes on the target
+// commits, and patch IDs when the patch_id strategy needs them.
+func filterUnpicked(ctx context.Context, repoDir string, opts picker.MatchOptions, sourceCommits, targetCommits []git.Commit, log *slog.Logger) ([]git.Commit, error) {
+	all, err := marks.Load(ctx, repoDir)
+	if err != nil {
+		return nil, err
+	}
+	opts.Marks = marks.NewSet(all)
+	if err := provenance.Load(ctx, repoDir, targetCommits); err != nil {
+		return nil, err
+	}
+
+	if opts.Uses(picker.StrategyPatchID) {
+		if err := git.LoadPatchIDs(ctx, repoDir, sourceCommits); err != nil {
+			return nil, err
+		}
+		if err := git.LoadPatchIDs(ctx, repoDir, targetCommits); err != nil {
+			return nil, err
+		}
+	}
+	return picker.FilterUnpickedCommits(sourceCommits, targetCommits, opts, log), nil
+}
+
+// excludeAlreadyApplied drops commits an earlier session found already applied on the
+// target. They have no matching commit there, so the matcher alone would list them again.
+func excludeAlreadyApplied(ctx context.Context, repoDir string, commits []git.Commit, sourceBranch, targetBranch string) []git.Commit {
+	gitDir, err := git.GetGitDir(ctx, repoDir)
+	if err != nil {
+		return commits
+	}
+	sessions, err := session.LoadAll(gitDir)
+	if err != nil {
+		return commits
+	}
+	return picker.ExcludeCommits(commits, session.AlreadyApplied(sessions, sourceBranch, targetBranch))
+}
+
+// recordSession appends a pick session to the repository's session log
+func recordSession(ctx context.Context, log *slog.Logger, repoDir string, s session.Session) {
+	gitDir, err := git.GetGitDir(ctx, repoDir)
+	if err == nil {
+		err = session.Append(gitDir, s)
+	}
+	if err != nil {
+		log.Warn("failed to record pick session", "err", err)
+	}
+}
+
+// recordProvenance notes on each commit a session made where it was picked from,
+// naming the source commit by its full hash
+func recordProvenance(ctx context.Context, log *slog.Logger, repoDir string, s session.Session) {
+	var picks []git.PickResult
+	var sources []string
+	for _, pick := range s.Picks {
+		if pick.NewHash != "" {
+			picks = append(picks, pick)
+			sources = append(sources, pick.Source)
+		}
+	}
+	full, err := git.GetCommitHashes(ctx, repoDir, sources)
+	if err != nil {
+		// Abbreviated hashes still match; they are just less durable
+		log.Debug("failed to resolve source commits", "err", err)
+		full = sources
+	}
+
+	notes := make(map[string]provenance.Note, len(picks))
+	for i, pick := range picks {
+		notes[pick.NewHash] = provenance.Note{
+			Source:       full[i],
+			SourceBranch: s.SourceBranch,
+			Card:         s.Card,
+			Session:      s.ID,
+			User:         s.User,
+		}
+	}
+	if err := provenance.Record(ctx, repoDir, notes); err != nil {
+		log.Warn("failed to record where picked commits came from", "err", err)
+	}
+}
+
+// sessionBranch returns the branch a session picks onto
+func sessionBranch(s session.Session) string {
+	if s.PickBranch != "" {
+		return s.PickBranch
+	}
+	return s.TargetBranch
+}
+
+// checkSessionBranch makes sure the branch a session picked onto is checked out
+// before resuming or undoing it
+func checkSessionBranch(ctx context.Context, repoDir string, s session.Session) error {
+	current, err := git.GetCurrentBranch(ctx, repoDir)
+	if err != nil {
+		return err
+	}
+	if branch := sessionBranch(s); current != branch {
+		return fmt.Errorf("the last pick was onto %s but %s is checked out; switch back to %s first", branch, current, branch)
+	}
+	return nil
+}
+
+// cardBranches holds the PRD/HML branch pair derived from the current branch
+type cardBranches struct {
+	Current string
+	Card    string
+	Prd     string
+	Hml     string
+}
+
+// resolveCardBranches parses the current branch and builds the card's PRD/HML branch names
+func resolveCardBranches(ctx context.Context, repoDir string, cfg *config.Config) (*cardBranches, error) {
+	if err := git.CheckRepository(ctx, repoDir); err != nil {
+		return nil, err
+	}
+
+	currentBranch, err := git.GetCurrentBranch(ctx, repoDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current branch: %w", err)
+	}
+
+	var currentSuffix string
+	if strings.HasSuffix(currentBranch, cfg.SuffixPrd) {
+		currentSuffix = cfg.SuffixPrd
+	} else if strings.HasSuffix(currentBranch, cfg.SuffixHml) {
+		currentSuffix = cfg.SuffixHml
+	} else {
+		return nil, fmt.Errorf("current branch '%s' doesn't end with PRD suffix '%s' or HML suffix '%s'", currentBranch, cfg.SuffixPrd, cfg.SuffixHml)
+	}
+
+	branchIdentifier, err := git.ParseBranchName(currentBranch, cfg.Prefix, currentSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse branch name: %w", err)
+	}
+
+	return &cardBranches{
+		Current: currentBranch,
+		Card:    branchIdentifier,
+		Prd:     cfg.Prefix + branchIdentifier + cfg.SuffixPrd,
+		Hml:     cfg.Prefix + branchIdentifier + cfg.SuffixHml,
+	}, nil
+}
+
+// shouldFetch decides whether the card branches are fetched before comparing.
+// Without --fetch or --no-fetch, chr only fetches when a branch is missing locally.
+func (p *PickCmd) shouldFetch(ctx context.Context, repoDir string, branches ...string) bool {
+	if p.NoFetch {
+		return false
+	}
+	if p.Fetch || p.Remote {
+		return true
+	}
+	for _, branch := range branches {
+		if exists, _ := git.BranchExists(ctx, repoDir, branch); !exists {
+			return true
+		}
+	}
+	return false
+}
+
+// checkBranchExists succeeds when a branch exists locally or as a remote-tracking ref.
+// With --remote only the remote-tracking ref counts.
+func (p *PickCmd) checkBranchExists(ctx context.Context, repoDir, remote, branch string) error {
+	if !p.Remote {
+		if exists, err := git.BranchExists(ctx, repoDir, branch); err != nil {
+			return err
+		} else if exists {
+			return nil
+		}
+	}
+	if exists, err := git.BranchExists(ctx, repoDir, git.RemoteRef(remote, branch)); err != nil {
+		return err
+	} else if exists {
+		return nil
+	}
+	return &git.BranchNotFoundError{Branch: branch}
+}
+
+// resolveRef picks the ref used to read a branch: the remote-tracking ref with
+// --remote, otherwise the local branch when it exists. A branch missing on the
+// remote falls back to the local one even with --remote.
+func (p *PickCmd) resolveRef(ctx context.Context, repoDir, remote, branch string) string {
+	if p.Remote {
+		remoteRef := git.RemoteRef(remote, branch)
+		if exists, _ := git.BranchExists(ctx, repoDir, remoteRef); exists {
+			return remoteRef
+		}
+		p.log.Debug("remote branch not found, using local", "branch", branch, "ref", remoteRef)
+	}
+	return git.ResolveRef(ctx, repoDir, remote, branch, p.log)
+}
+
+// checkSync compares local card branches with their remote-tracking refs. A branch
+// that is only behind produces a warning; diverged branches fail unless --remote is
+// used, since local and remote would give different "already picked" answers.
+func (p *PickCmd) checkSync(ctx context.Context, repoDir, remote string, branches ...string) error {
+	for _, branch := range branches {
+		status, err := git.GetSyncStatus(ctx, repoDir, remote, branch)
+		if err != nil {
+			return fmt.Errorf("failed to compare %s with %s: %w", branch, git.RemoteRef(remote, branch), err)
+		}
+
+		if status.HasLocal && status.HasRemote {
+			p.log.Debug("branch sync status", "branch", branch, "ahead", status.Ahead, "behind", status.Behind, "remote", status.RemoteRef)
+		}
+
+		switch {
+		case status.Diverged() && p.Remote:
+			p.log.Warn("local branch has diverged; using the remote",
+				"branch", branch, "remote", status.RemoteRef, "ahead", status.Ahead, "behind", status.Behind)
+		case status.Diverged():
+			return fmt.Errorf("local branch %s has diverged from %s (%d ahead, %d behind); sync it or use --remote",
+				branch, status.RemoteRef, status.Ahead, status.Behind)
+		case status.Behind > 0 && !p.Remote:
+			p.log.Warn("local branch is behind the remote", "branch", branch, "remote", status.RemoteRef, "behind", status.Behind)
+		}
+	}
+	return nil
+}
+
+// pushTarget pushes the target branch to the remote and reports the pushed commit
+func pushTarget(ctx context.Context, repoDir, remote, targetBranch string, forceWithLease bool) error {
+	fmt.Printf("Pushing %s to %s...\n", targetBranch, remote)
+
+	sha, err := git.PushBranch(ctx, repoDir, remote, targetBranch, forceWithLease)
+	if err != nil {
+		return fmt.Errorf("push failed: %w", err)
+	}
+
+	fmt.Printf("✓ Pushed %s to %s at %s\n", targetBranch, remote, sha)
+	return nil
+}
+
+// newForgeClient builds the pull/merge request client from the forge settings.
+// Without forge_repo, the repository is derived from the configured remote's URL.
+func newForgeClient(ctx context.Context, repoDir string, cfg *config.Config) (forge.Client, error) {
+	repo := cfg.ForgeRepo
+	if repo == "" {
+		remoteURL, err := git.GetRemoteURL(ctx, repoDir, cfg.Remote)
+		if err != nil {
+			return nil, err
+		}
+		repo, err = forge.RepoFromRemoteURL(remoteURL)
+		if err != nil {
+			return nil, fmt.Errorf("%w; set forge_repo", err)
+		}
+	}
+
+	client, err := forge.NewClient(cfg.Forge, cfg.ForgeURL, repo, forge.TokenFromEnv(cfg.Forge))
+	if err != nil {
+		return nil, fmt.Errorf("cannot open pull requests: %w", err)
+	}
+	return client, nil
+}
+
+// openPullRequest pushes the session's pick branch and opens a pull/merge request
+// into the target branch, listing the picked commits in its body
+func openPullRequest(ctx context.Context, repoDir string, cfg *config.Config, s session.Session, report session.Report) error {
+	picked := report.Count(git.PickStatusPicked)
+	if picked == 0 {
+		fmt.Printf("Nothing was picked onto %s; not opening a pull request.\n", s.PickBranch)
+		return nil
+	}
+
+	client, err := newForgeClient(ctx, repoDir, cfg)
+	if err != nil {
+		return err
+	}
+
+	if err := pushTarget(ctx, repoDir, cfg.Remote, s.PickBranch, false); err != nil {
+		return err
+	}
+
+	created, err := client.CreatePullRequest(forge.PullRequest{
+		Title: fmt.Sprintf("%s%s: pick %d commits from %s into %s", cfg.Prefix, s.Card, picked, s.SourceBranch, s.TargetBranch),
+		Body:  report.Markdown(),
+		Head:  s.PickBranch,
+		Base:  s.TargetBranch,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Opened pull request #%d: %s\n", created.Number, created.URL)
 	return nil
 }
 
 // Run executes the config command
-func (c *ConfigCmd) Run(ctx *kong.Context, globals *CLI) error {
+func (c *ConfigCmd) Run(ctx context.Context, globals *CLI) error {
 	color.NoColor = globals.NoColor
 
 	// Check if both key and value are provided for setting
@@ -277,8 +982,8 @@ func (c *ConfigCmd) Run(ctx *kong.Context, globals *CLI) error {
 			return err
 		}
 
-		// Load current config
-		cfg, err := loadConfig()
+		// Load the file alone so environment overrides are not written back
+		cfg, err := config.LoadFileConfig("")
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
@@ -316,7 +1021,7 @@ func (c *ConfigCmd) Run(ctx *kong.Context, globals *CLI) error {
 	return nil
 }
 
-func (v *VersionCmd) Run(ctx *kong.Context, globals *CLI) error {
+func (v *VersionCmd) Run(ctx context.Context, globals *CLI) error {
 	fmt.Println("chr version 0.1.4")
 	return nil
 }
@@ -349,13 +1054,14 @@ func (cli *CLI) BeforeApply(ctx *kong.Context) error {
 	return nil
 }
 
-// ExecuteCLI runs the CLI application
-func ExecuteCLI(args []string) error {
+// ExecuteCLI runs the CLI application. Commands receive ctx and stop the git
+// commands they run once it is cancelled.
+func ExecuteCLI(ctx context.Context, args []string) error {
 	var cli CLI
 
 	parser, err := kong.New(&cli,
 		kong.Name("chr"),
-		kong.Description("Git commit manager for cherry-picking between production and homologation branches\n\nUsage: chr pick [flags]         # Cherry-pick PRD to HML (default)\n       chr pick --reverse       # Cherry-pick HML to PRD\n       chr pick --show          # Show commits (dry run)\n       chr config               # Manage configuration"),
+		kong.Description("Git commit manager for cherry-picking between production and homologation branches\n\nUsage: chr pick [flags]         # Cherry-pick PRD to HML (default)\n       chr pick --reverse       # Cherry-pick HML to PRD\n       chr pick --show          # Show commits (dry run)\n       chr status               # Show card branch status\n       chr notes                # Release notes for pending commits\n       chr ignore <commit>      # Never list a commit as unpicked\n       chr config               # Manage configuration"),
 		kong.UsageOnError(),
 		kong.ConfigureHelp(kong.HelpOptions{
 			Compact: true,
@@ -365,18 +1071,37 @@ func ExecuteCLI(args []string) error {
 		return fmt.Errorf("failed to create parser: %w", err)
 	}
 
-	ctx, err := parser.Parse(args)
+	kctx, err := parser.Parse(args)
 	if err != nil {
 		return err
 	}
 
+	logOpts := logging.Options{
+		Level:   logging.LevelFromFlags(cli.Quiet, cli.Verbose, cli.Debug),
+		Console: os.Stderr,
+	}
+	if cli.LogFile != "" {
+		logFile, err := os.OpenFile(cli.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		defer logFile.Close()
+		logOpts.File = logFile
+	}
+	cli.logger = logging.New(logOpts)
+
+	if cli.PrintCommands {
+		ctx = git.WithRunner(ctx, &git.TraceRunner{Next: git.RunnerFrom(ctx), Out: os.Stderr})
+	}
+
 	// Apply global interceptor
-	if err := cli.BeforeApply(ctx); err != nil {
+	if err := cli.BeforeApply(kctx); err != nil {
 		return err
 	}
 
 	// Run the selected command
-	return ctx.Run(ctx, &cli)
+	kctx.BindTo(ctx, (*context.Context)(nil))
+	return kctx.Run(&cli)
 }
 
 // Helper functions for testing
@@ -386,10 +1111,32 @@ func SetupTestColors(noColor bool) {
 
 func ValidateConfigKey(key string) error {
 	validKeys := map[string]bool{
-		"prefix":     true,
-		"suffix_prd": true,
-		"suffix_hml": true,
-		"color":      true,
+		"prefix":           true,
+		"suffix_prd":       true,
+		"suffix_hml":       true,
+		"color":            true,
+		"push_after_pick":  true,
+		"remote":           true,
+		"ticket_pattern":   true,
+		"ticket_url":       true,
+		"forge":            true,
+		"forge_url":        true,
+		"forge_repo":       true,
+		"jira_url":         true,
+		"jira_user":        true,
+		"jira_token":       true,
+		"jira_comment":     true,
+		"jira_transition":  true,
+		"merge_policy":     true,
+		"merge_mainline":   true,
+		"empty_policy":     true,
+		"backend":          true,
+		"match_strategies": true,
+		"match_min_score":  true,
+
+		"notify.webhooks":        true,
+		"notify.timeout_seconds": true,
+		"notify.retries":         true,
 	}
 
 	if !validKeys[key] {
@@ -401,14 +1148,55 @@ func ValidateConfigKey(key string) error {
 
 func ValidateConfigValue(key, value string) error {
 	switch key {
-	case "color":
+	case "color", "push_after_pick", "jira_comment":
 		if _, err := strconv.ParseBool(value); err != nil {
-			return fmt.Errorf("color must be true or false")
+			return fmt.Errorf("%s must be true or false", key)
 		}
-	case "prefix", "suffix_prd", "suffix_hml":
+	case "prefix", "suffix_prd", "suffix_hml", "remote":
 		if value == "" {
 			return fmt.Errorf("%s cannot be empty", key)
 		}
+	case "merge_policy":
+		switch value {
+		case picker.MergePolicySkip, picker.MergePolicyMainline, picker.MergePolicyExpand:
+		default:
+			return fmt.Errorf("merge_policy must be %s, %s or %s", picker.MergePolicySkip, picker.MergePolicyMainline, picker.MergePolicyExpand)
+		}
+	case "empty_policy":
+		switch value {
+		case git.EmptySkip, git.EmptyKeep, git.EmptyStop:
+		default:
+			return fmt.Errorf("empty_policy must be %s, %s or %s", git.EmptySkip, git.EmptyKeep, git.EmptyStop)
+		}
+	case "merge_mainline":
+		if n, err := strconv.Atoi(value); err != nil || n < 1 {
+			return fmt.Errorf("merge_mainline must be a positive integer")
+		}
+	case "notify.timeout_seconds", "notify.retries":
+		if n, err := strconv.Atoi(value); err != nil || n < 0 {
+			return fmt.Errorf("%s must be a non-negative integer", key)
+		}
+	case "match_strategies":
+		if _, err := picker.ParseStrategies(value); err != nil {
+			return err
+		}
+	case "match_min_score":
+		if n, err := strconv.Atoi(value); err != nil || n < 0 || n > 100 {
+			return fmt.Errorf("match_min_score must be an integer from 0 to 100")
+		}
+	case "backend":
+		if value != git.BackendGit && value != git.BackendGoGit {
+			return fmt.Errorf("backend must be %s or %s", git.BackendGit, git.BackendGoGit)
+		}
+	case "forge":
+		if value != forge.KindGitHub && value != forge.KindGitLab {
+			return fmt.Errorf("forge must be %s or %s", forge.KindGitHub, forge.KindGitLab)
+		}
+	case "ticket_pattern":
+		cfg := config.Config{TicketPattern: value}
+		if _, err := cfg.TicketRegexp(); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -419,6 +1207,18 @@ func loadConfig() (*config.Config, error) {
 	return config.LoadConfig("")
 }
 
+// useBackend returns ctx carrying the configured read-only backend
+func useBackend(ctx context.Context, cfg *config.Config) (context.Context, error) {
+	if err := ValidateConfigValue("backend", cfg.Backend); err != nil {
+		return nil, err
+	}
+	backend, err := git.NewBackend(cfg.Backend)
+	if err != nil {
+		return nil, err
+	}
+	return git.WithBackend(ctx, backend), nil
+}
+
 func saveConfig(cfg *config.Config) error {
 	return config.SaveConfig("", cfg)
 }
@@ -426,7 +1226,7 @@ func saveConfig(cfg *config.Config) error {
 func runInteractiveConfig() error {
 	fmt.Println("Interactive configuration setup:")
 
-	cfg, err := loadConfig()
+	cfg, err := config.LoadFileConfig("")
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
@@ -435,6 +1235,17 @@ func runInteractiveConfig() error {
 	fmt.Printf("Current production suffix: %s\n", cfg.SuffixPrd)
 	fmt.Printf("Current homologation suffix: %s\n", cfg.SuffixHml)
 	fmt.Printf("Current color setting: %v\n", cfg.Color)
+	fmt.Printf("Current push after pick setting: %v\n", cfg.PushAfterPick)
+	fmt.Printf("Current remote: %s\n", cfg.Remote)
+	fmt.Printf("Current ticket pattern: %s\n", cfg.TicketPattern)
+	fmt.Printf("Current ticket URL: %s\n", cfg.TicketURL)
+	fmt.Printf("Current forge: %s (url: %s, repo: %s)\n", cfg.Forge, cfg.ForgeURL, cfg.ForgeRepo)
+	fmt.Printf("Current Jira: %s (comment: %v, transition: %s)\n", cfg.JiraURL, cfg.JiraComment, cfg.JiraTransition)
+	fmt.Printf("Current merge policy: %s (mainline: %d)\n", cfg.MergePolicy, cfg.MergeMainline)
+	fmt.Printf("Current empty policy: %s\n", cfg.EmptyPolicy)
+	fmt.Printf("Current backend: %s\n", cfg.Backend)
+	fmt.Printf("Current match strategies: %s (min score: %d)\n", strings.Join(cfg.MatchStrategies, ","), cfg.MatchMinScore)
+	fmt.Printf("Current webhooks: %s\n", strings.Join(cfg.Notify.Webhooks, ", "))
 
 	// TODO: Add actual interactive prompts (would need a prompt library)
 	fmt.Println("(Interactive prompts not yet implemented - use --set instead)")
@@ -442,11 +1253,33 @@ func runInteractiveConfig() error {
 	return nil
 }
 
+// messageTypeColors maps Conventional Commits types to message colors
+var messageTypeColors = map[string][]color.Attribute{
+	"feat":     {color.FgGreen},
+	"fix":      {color.FgRed},
+	"docs":     {color.FgCyan},
+	"refactor": {color.FgMagenta},
+	"perf":     {color.FgYellow},
+	"test":     {color.FgBlue},
+	"revert":   {color.FgHiRed},
+	"build":    {color.FgHiBlack},
+	"ci":       {color.FgHiBlack},
+	"chore":    {color.FgHiBlack},
+	"style":    {color.FgHiBlack},
+}
+
 // displayCommit formats and displays a commit with optional colors
 func displayCommit(index int, commit git.Commit, currentUser string, enableColor bool) {
+	note := ""
+	if commit.IsMerge() {
+		note = " [merge]"
+	} else if commit.Via != "" {
+		note = fmt.Sprintf(" [via merge %s]", commit.Via)
+	}
+
 	if !enableColor || color.NoColor {
 		// Plain text output
-		fmt.Printf("%d. %s | %s | %s | %s\n", index, commit.Hash, commit.Author, commit.Date, commit.Message)
+		fmt.Printf("%d. %s | %s | %s | %s%s\n", index, commit.Hash, commit.Author, commit.Date, commit.Message, note)
 		return
 	}
 
@@ -458,25 +1291,27 @@ func displayCommit(index int, commit git.Commit, currentUser string, enableColor
 		authorColor = color.New(color.FgRed)
 	}
 	dateColor := color.New(color.FgBlue)
-	messageColor := color.New(color.FgWhite)
-
 	// Check message type for different colors
-	if strings.HasPrefix(commit.Message, "feat:") {
-		messageColor = color.New(color.FgGreen)
-	} else if strings.HasPrefix(commit.Message, "fix:") {
-		messageColor = color.New(color.FgRed)
-	} else if strings.HasPrefix(commit.Message, "docs:") {
-		messageColor = color.New(color.FgCyan)
-	} else if strings.HasPrefix(commit.Message, "refactor:") {
-		messageColor = color.New(color.FgMagenta)
+	parsed := conventional.Parse(commit.FullMessage())
+	messageColor := color.New(messageTypeColors[parsed.Type]...)
+	if len(messageTypeColors[parsed.Type]) == 0 {
+		messageColor = color.New(color.FgWhite)
+	}
+	if parsed.Breaking {
+		messageColor.Add(color.Bold)
+	}
+
+	if note != "" {
+		note = color.New(color.FgMagenta).Sprint(note)
 	}
 
-	fmt.Printf("%s %s | %s | %s | %s\n",
+	fmt.Printf("%s %s | %s | %s | %s%s\n",
 		indexColor.Sprintf("%d.", index),
 		hashColor.Sprint(commit.Hash),
 		authorColor.Sprint(commit.Author),
 		dateColor.Sprint(commit.Date),
 		messageColor.Sprint(commit.Message),
+		note,
 	)
 }
 
@@ -579,6 +1414,18 @@ chr identifies commits using:
 
 This means rebased commits are still correctly identified and not duplicated.
 
+### Ignored and Marked Commits
+- ` + "`chr ignore <hash>`" + ` hides a commit that should never be picked (e.g. PRD-only config), even with ` + "`--no-filter`" + `
+- ` + "`chr mark-picked <hash> --as <target-hash>`" + ` records a commit picked by other means
+- ` + "`chr ignore --list`" + ` shows both; ` + "`chr ignore --remove <hash>`" + ` forgets either
+- Marks are git notes in ` + "`refs/notes/chr-marks`" + `, shared with ` + "`git push origin refs/notes/chr-marks`" + `
+
+### Provenance Notes
+- Every pick notes the source commit, branch, card, session and user on the new commit in ` + "`refs/notes/chr`" + `
+- Matching trusts these notes first, so rewording, redating or squashing a picked commit doesn't make it unpicked
+- The first pick adds ` + "`refs/notes/chr`" + ` to the repository's local ` + "`notes.rewriteRef`" + ` config so rewrites keep the notes
+- Share them with ` + "`git push origin refs/notes/chr`" + `
+
 ### Dry-Run by Default
 - ` + "`chr`" + ` always shows commits first (safe preview)
 - ` + "`chr --pick`" + ` actually performs the cherry-pick
@@ -610,6 +1457,7 @@ CHR_COLOR="false"
 - **Not in Git repo**: Current directory isn't a Git repository
 - **No commits found**: No commits match the criteria
 - **Cherry-pick conflicts**: Provides clear guidance on conflict resolution
+- **Interrupted picks**: Ctrl-C stops between commits; resume with ` + "`chr pick --continue`" + ` or undo with ` + "`chr pick --abort`" + `
 
 ## Best Practices for LLM Integration
 1. **Always start with dry-run** (` + "`chr`" + `) to preview commits
@@ -628,37 +1476,181 @@ chr excels at eliminating the manual work of identifying and cherry-picking comm
 `)
 }
 
-func handleContinue() error {
+func (p *PickCmd) handleContinue(ctx context.Context) error {
 	repoDir, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get current directory: %w", err)
 	}
 
-	cherryPickHeadPath := fmt.Sprintf("%s/.git/CHERRY_PICK_HEAD", repoDir)
-	if _, err := os.Stat(cherryPickHeadPath); os.IsNotExist(err) {
+	gitDir, err := git.GetGitDir(ctx, repoDir)
+	if err != nil {
+		return err
+	}
+	last, err := session.Last(gitDir)
+	if err != nil {
+		return err
+	}
+
+	// A session can be resumed with nothing in progress: it was interrupted or
+	// failed between two commits, or the conflict was committed with plain
+	// git cherry-pick --continue
+	inProgress := git.CherryPickInProgress(ctx, repoDir)
+	resumable := last != nil && last.Resumable()
+	if !inProgress && !resumable {
 		fmt.Println("No cherry-pick in progress. Nothing to continue.")
 		return nil
 	}
 
-	fmt.Println("Continuing cherry-pick...")
+	// A commit whose changes are already on the target has nothing to commit
+	empty := false
+	if inProgress {
+		empty = git.CherryPickIsEmpty(ctx, repoDir)
+		if empty {
+			fmt.Println("Nothing to commit: the change is already applied. Skipping it...")
+			if err := git.SkipCherryPick(ctx, repoDir); err != nil {
+				return err
+			}
+		} else {
+			fmt.Println("Continuing cherry-pick...")
+			if err := git.ContinueCherryPick(ctx, repoDir); err != nil {
+				return err
+			}
+
+			fmt.Println("✓ Cherry-pick completed successfully!")
+		}
+	}
+
+	// Resume the rest of the last chr session, if it stopped midway
+	if !resumable {
+		return nil
+	}
+	if !inProgress {
+		if err := checkSessionBranch(ctx, repoDir, *last); err != nil {
+			return err
+		}
+		if err := git.CheckCleanTree(ctx, repoDir); err != nil {
+			return err
+		}
+	}
+
+	commits := make(map[string]git.Commit, len(last.Commits))
+	for _, commit := range last.Commits {
+		commits[commit.Hash] = commit
+	}
+
+	var pending []git.Commit
+	for _, pick := range last.Picks {
+		if pick.Status == git.PickStatusPending {
+			pending = append(pending, commits[pick.Source])
+		}
+	}
+
+	// The commit a conflicted or stopped session stopped on is the one right
+	// before the pending ones; an interrupted or failed one left none behind
+	midway := last.Outcome == session.OutcomeInterrupted || last.Outcome == session.OutcomeFailed
+	if stoppedAt := len(last.Picks) - len(pending) - 1; !midway && stoppedAt >= 0 {
+		head, err := git.GetCommitHash(ctx, repoDir, "HEAD")
+		if err != nil {
+			return err
+		}
+		switch {
+		case empty:
+			last.Picks[stoppedAt].Status = git.PickStatusAlreadyApplied
+		case inProgress || head != headBefore(*last, stoppedAt):
+			// Committed above, or by hand with git cherry-pick --continue
+			last.Picks[stoppedAt].NewHash = head
+			last.Picks[stoppedAt].Status = git.PickStatusPicked
+		}
+	}
+
+	results, pickErr := git.CherryPickCommits(ctx, repoDir, pending, git.PickOptions{Mainline: last.Mainline, Empty: last.EmptyPolicy})
+	last.Picks = append(last.Picks[:len(last.Picks)-len(pending)], results...)
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	return p.finishSession(ctx, repoDir, cfg, *last, pickErr)
+}
+
+// headBefore returns the commit a session's branch was on before picks[i]:
+// the last commit picked before it, or where the pick started
+func headBefore(s session.Session, i int) string {
+	for j := i - 1; j >= 0; j-- {
+		if s.Picks[j].NewHash != "" {
+			return s.Picks[j].NewHash
+		}
+	}
+	return s.StartHead
+}
+
+// handleAbort undoes the last session if it did not finish: the cherry-pick in
+// progress, if any, is aborted and the branch is reset to where the pick started
+func (p *PickCmd) handleAbort(ctx context.Context) error {
+	repoDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	gitDir, err := git.GetGitDir(ctx, repoDir)
+	if err != nil {
+		return err
+	}
+	last, err := session.Last(gitDir)
+	if err != nil {
+		return err
+	}
+
+	inProgress := git.CherryPickInProgress(ctx, repoDir)
+	if last == nil || !last.Resumable() {
+		if inProgress {
+			return fmt.Errorf("the cherry-pick in progress was not started by chr; abort it with git cherry-pick --abort")
+		}
+		fmt.Println("No unfinished pick. Nothing to abort.")
+		return nil
+	}
+	if last.StartHead == "" {
+		return fmt.Errorf("session %s did not record where it started; undo it with git cherry-pick --abort and git reset", last.ID)
+	}
+	if err := checkSessionBranch(ctx, repoDir, *last); err != nil {
+		return err
+	}
 
-	cmd := exec.Command("git", "cherry-pick", "--continue")
-	cmd.Dir = repoDir
-	if err := cmd.Run(); err != nil {
-		statusCmd := exec.Command("git", "status", "--porcelain")
-		statusCmd.Dir = repoDir
-		statusOutput, _ := statusCmd.Output()
+	// Commits made on top of the pick since are not chr's to drop
+	if ok, err := git.IsAncestor(ctx, repoDir, last.StartHead, "HEAD"); err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("HEAD no longer descends from %s, where the pick started; reset the branch by hand", session.ShortHash(last.StartHead))
+	}
+	dropped, _, err := git.GetAheadBehind(ctx, repoDir, "HEAD", last.StartHead)
+	if err != nil {
+		return err
+	}
 
-		if len(statusOutput) > 0 {
-			fmt.Println("Still have conflicts to resolve:")
-			fmt.Printf("%s", string(statusOutput))
-			fmt.Println("\nResolve conflicts and run 'chr pick --continue' again.")
+	if inProgress {
+		if err := git.AbortCherryPick(ctx, repoDir); err != nil {
+			return err
 		}
+	}
+	if err := git.ResetHard(ctx, repoDir, last.StartHead); err != nil {
+		return err
+	}
+
+	last.Outcome = session.OutcomeAborted
+	recordSession(ctx, p.log, repoDir, *last)
 
-		return fmt.Errorf("cherry-pick continue failed: %v", err)
+	// The branch is already reset, so a broken config only costs the notification
+	if cfg, err := loadConfig(); err != nil {
+		p.log.Warn("skipping notifications", "err", err)
+	} else {
+		sendNotifications(p.log, cfg, notify.EventAborted, last.Card, session.NewReport(*last))
 	}
 
-	fmt.Println("✓ Cherry-pick completed successfully!")
+	fmt.Printf("Aborted pick session %s: %s is back at %s (%d picked commits dropped).\n",
+		last.ID, sessionBranch(*last), session.ShortHash(last.StartHead), dropped)
+	if last.PickBranch != "" {
+		fmt.Printf("The pick branch %s was kept; switch away and delete it with git branch -D %s.\n", last.PickBranch, last.PickBranch)
+	}
 	return nil
 }
 
@@ -686,6 +1678,9 @@ chr config --set-key suffix_hml --set-value "-staging"
 # Enable/disable colored output
 chr config --set-key color --set-value false
 
+# Always push the target branch after picking
+chr config --set-key push_after_pick --set-value true
+
 # Interactive setup (not yet implemented - use --set-key instead)
 chr config --setup
 ` + "```" + `
@@ -695,6 +1690,24 @@ chr config --setup
 - **suffix_prd**: Production branch suffix (default: "-prd")  
 - **suffix_hml**: Homologation branch suffix (default: "-hml")
 - **color**: Enable colored output (default: true)
+- **push_after_pick**: Push the target branch after a successful pick (default: false)
+- **remote**: Remote used for fetching and pushing card branches (default: "origin")
+- **ticket_pattern**: Regex for ticket IDs used by ` + "`chr pick --ticket`" + `; {prefix} is the card prefix (default: "{prefix}[0-9]+")
+- **ticket_url**: Link template for tickets in ` + "`chr notes`" + `, e.g. "https://jira.example.com/browse/{ticket}" (default: empty)
+- **forge**: Where ` + "`chr pick --pr`" + ` opens pull/merge requests: github or gitlab (default: github)
+- **forge_url**: Forge API base URL (default: the public GitHub/GitLab API)
+- **forge_repo**: Repository as owner/name or GitLab project path (default: derived from the remote URL)
+- **jira_url**, **jira_user**, **jira_token**: Jira base URL and credentials (token also via CHR_JIRA_TOKEN)
+- **jira_comment**: Comment on the card's Jira issue after a successful pick (default: false)
+- **jira_transition**: Move the card's Jira issue to this status after a successful pick (default: empty)
+- **merge_policy**: Merge commits in the source branch: skip, mainline (pick with -m) or expand (default: skip)
+- **merge_mainline**: Parent number merges are picked relative to with merge_policy mainline (default: 1)
+- **empty_policy**: Commits already applied on the target: skip, keep (as empty commits) or stop (default: skip)
+- **backend**: How refs, logs and the user are read: git (the git CLI) or go-git (in-process) (default: git)
+- **match_strategies**: How already-picked commits are recognized, in priority order: exact, author_subject, patch_id, fuzzy, trailer (default: exact,author_subject)
+- **match_min_score**: Lowest match score (0-100) that counts a commit as picked (default: 0)
+- **notify.webhooks**: Comma-separated webhook URLs posted after each pick, conflict or abort (default: none)
+- **notify.timeout_seconds**, **notify.retries**: Per-request timeout and retry count for webhooks (default: 5, 2)
 
 ## Configuration Sources (Priority Order)
 1. **Command-line flags** (highest priority)
@@ -733,6 +1746,8 @@ prefix = "ACME-"
 suffix_prd = "-production"
 suffix_hml = "-staging"
 color = true
+push_after_pick = false
+remote = "origin"
 ` + "```" + `
 
 ## Environment Variable Override
@@ -749,8 +1764,8 @@ export CHR_COLOR="false"
 
 ## Validation
 chr validates configuration values:
-- **prefix, suffix_prd, suffix_hml**: Cannot be empty
-- **color**: Must be true or false
+- **prefix, suffix_prd, suffix_hml, remote**: Cannot be empty
+- **color, push_after_pick**: Must be true or false
 
 Invalid configurations will show clear error messages with suggestions.
 `)
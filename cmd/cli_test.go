@@ -43,10 +43,6 @@ func TestPickCmd_DefaultValues(t *testing.T) {
 		t.Error("Continue flag should be false by default")
 	}
 	
-	if pickCmd.Debug {
-		t.Error("Debug flag should be false by default")
-	}
-	
 	if pickCmd.NoFilter {
 		t.Error("NoFilter flag should be false by default")
 	}
@@ -58,7 +54,7 @@ func TestPickCmd_FlagCombinations(t *testing.T) {
 		reverse  bool
 		show     bool
 		latest   bool
-		debug    bool
+		noFilter bool
 		expected string
 	}{
 		{
@@ -66,7 +62,7 @@ func TestPickCmd_FlagCombinations(t *testing.T) {
 			reverse:  false,
 			show:     false,
 			latest:   false,
-			debug:    false,
+			noFilter: false,
 			expected: "normal cherry-pick from PRD to HML",
 		},
 		{
@@ -74,7 +70,7 @@ func TestPickCmd_FlagCombinations(t *testing.T) {
 			reverse:  true,
 			show:     false,
 			latest:   false,
-			debug:    false,
+			noFilter: false,
 			expected: "reverse cherry-pick from HML to PRD",
 		},
 		{
@@ -82,7 +78,7 @@ func TestPickCmd_FlagCombinations(t *testing.T) {
 			reverse:  false,
 			show:     true,
 			latest:   false,
-			debug:    false,
+			noFilter: false,
 			expected: "dry-run mode showing PRD commits",
 		},
 		{
@@ -90,7 +86,7 @@ func TestPickCmd_FlagCombinations(t *testing.T) {
 			reverse:  true,
 			show:     true,
 			latest:   false,
-			debug:    false,
+			noFilter: false,
 			expected: "dry-run mode showing HML commits",
 		},
 		{
@@ -98,26 +94,26 @@ func TestPickCmd_FlagCombinations(t *testing.T) {
 			reverse:  true,
 			show:     false,
 			latest:   true,
-			debug:    false,
+			noFilter: false,
 			expected: "latest commits from HML to PRD",
 		},
 		{
-			name:     "reverse debug mode",
+			name:     "reverse no-filter mode",
 			reverse:  true,
 			show:     false,
 			latest:   false,
-			debug:    true,
-			expected: "debug mode reverse from HML to PRD",
+			noFilter: true,
+			expected: "unfiltered reverse from HML to PRD",
 		},
 	}
 	
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			pickCmd := &PickCmd{
-				Reverse: tt.reverse,
-				Show:    tt.show,
-				Latest:  tt.latest,
-				Debug:   tt.debug,
+				Reverse:  tt.reverse,
+				Show:     tt.show,
+				Latest:   tt.latest,
+				NoFilter: tt.noFilter,
 			}
 			
 			if pickCmd.Reverse != tt.reverse {
@@ -132,8 +128,8 @@ func TestPickCmd_FlagCombinations(t *testing.T) {
 				t.Errorf("Expected Latest to be %v, got %v", tt.latest, pickCmd.Latest)
 			}
 			
-			if pickCmd.Debug != tt.debug {
-				t.Errorf("Expected Debug to be %v, got %v", tt.debug, pickCmd.Debug)
+			if pickCmd.NoFilter != tt.noFilter {
+				t.Errorf("Expected NoFilter to be %v, got %v", tt.noFilter, pickCmd.NoFilter)
 			}
 		})
 	}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/carlosarraes/chr/internal/git"
+	"github.com/carlosarraes/chr/internal/picker"
+)
+
+// Exit codes returned by chr, so wrappers and CI can tell failures apart
+const (
+	ExitOK             = 0   // Success
+	ExitError          = 1   // Any other failure
+	ExitUsage          = 2   // Invalid flags or arguments
+	ExitNothingToPick  = 3   // No commit left to pick after filtering
+	ExitConflict       = 4   // Cherry-pick stopped on a conflict; resolve it and run chr pick --continue
+	ExitBranchNotFound = 5   // The card's PRD or HML branch does not exist
+	ExitNotRepository  = 6   // Not inside a git repository
+	ExitDirtyTree      = 7   // Tracked files have uncommitted changes
+	ExitAlreadyApplied = 8   // Cherry-pick stopped on an already-applied commit (empty_policy = "stop")
+	ExitInterrupted    = 130 // Interrupted by Ctrl-C or SIGTERM, like a shell reports SIGINT
+)
+
+// ExitCode maps an error returned by ExecuteCLI to the process exit code
+func ExitCode(err error) int {
+	var (
+		parseErr *kong.ParseError
+		conflict *git.ConflictError
+		applied  *git.AlreadyAppliedError
+		notFound *git.BranchNotFoundError
+		dirty    *git.DirtyTreeError
+	)
+
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.As(err, &parseErr):
+		return ExitUsage
+	case errors.Is(err, picker.ErrNothingToPick):
+		return ExitNothingToPick
+	case errors.As(err, &conflict):
+		return ExitConflict
+	case errors.As(err, &notFound):
+		return ExitBranchNotFound
+	case errors.Is(err, git.ErrNotRepository):
+		return ExitNotRepository
+	case errors.As(err, &dirty):
+		return ExitDirtyTree
+	case errors.As(err, &applied):
+		return ExitAlreadyApplied
+	case errors.Is(err, context.Canceled):
+		return ExitInterrupted
+	default:
+		return ExitError
+	}
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/carlosarraes/chr/internal/git"
+	"github.com/carlosarraes/chr/internal/picker"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, ExitOK},
+		{"generic", errors.New("boom"), ExitError},
+		{"usage", ExecuteCLI(context.Background(), []string{"--invalid-flag"}), ExitUsage},
+		{"nothing to pick", picker.ErrNothingToPick, ExitNothingToPick},
+		{"conflict", &git.ConflictError{Commit: "abc1234"}, ExitConflict},
+		{"wrapped branch missing", fmt.Errorf("HML %w", &git.BranchNotFoundError{Branch: "ZUP-1-hml"}), ExitBranchNotFound},
+		{"not a repository", fmt.Errorf("/tmp: %w", git.ErrNotRepository), ExitNotRepository},
+		{"dirty tree", &git.DirtyTreeError{Files: []string{"main.go"}}, ExitDirtyTree},
+		{"already applied", &git.AlreadyAppliedError{Commit: "abc1234"}, ExitAlreadyApplied},
+		{"interrupted pick", &git.InterruptedError{Pending: 2}, ExitInterrupted},
+		{"cancelled git command", fmt.Errorf("failed to fetch branches: %w", &git.CommandError{Err: context.Canceled}), ExitInterrupted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/carlosarraes/chr/internal/git"
+	"github.com/carlosarraes/chr/internal/picker"
+)
+
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// commitListing is the set of commits shown by chr pick, optionally grouped
+type commitListing struct {
+	SourceBranch  string               `json:"source_branch"`
+	TargetBranch  string               `json:"target_branch"`
+	GroupBy       string               `json:"group_by,omitempty"`
+	MergePolicy   string               `json:"merge_policy,omitempty"`
+	Mainline      int                  `json:"mainline,omitempty"`
+	SkippedMerges int                  `json:"skipped_merges,omitempty"`
+	Total         int                  `json:"total"`
+	Commits       []git.Commit         `json:"commits"`
+	Groups        []commitListingGroup `json:"groups,omitempty"`
+}
+
+type commitListingGroup struct {
+	Title   string       `json:"title"`
+	Count   int          `json:"count"`
+	Commits []git.Commit `json:"commits"`
+}
+
+// newCommitListing builds a listing, grouping commits when groupBy is set
+func newCommitListing(sourceBranch, targetBranch, groupBy string, commits []git.Commit) commitListing {
+	if commits == nil {
+		commits = []git.Commit{}
+	}
+
+	listing := commitListing{
+		SourceBranch: sourceBranch,
+		TargetBranch: targetBranch,
+		GroupBy:      groupBy,
+		Total:        len(commits),
+		Commits:      commits,
+	}
+
+	var groups []picker.CommitGroup
+	switch groupBy {
+	case "type":
+		groups = picker.GroupCommitsByMessage(commits)
+	case "author":
+		groups = picker.GroupCommitsByAuthor(commits)
+	case "date":
+		groups = picker.GroupCommitsByDate(commits)
+	}
+
+	for _, group := range groups {
+		listing.Groups = append(listing.Groups, commitListingGroup{
+			Title:   group.Title,
+			Count:   len(group.Commits),
+			Commits: group.Commits,
+		})
+	}
+
+	return listing
+}
+
+// writeListingJSON writes the listing as indented JSON
+func writeListingJSON(w io.Writer, listing commitListing) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(listing); err != nil {
+		return fmt.Errorf("failed to encode commits: %w", err)
+	}
+	return nil
+}
+
+// displayListing prints the listing, with a header per group when grouped
+func displayListing(listing commitListing, currentUser string, enableColor bool) {
+	fmt.Printf("\nFound %d unpicked commits:\n", listing.Total)
+	if note := mergePolicyNote(listing); note != "" {
+		fmt.Println(note)
+	}
+
+	if listing.GroupBy == "" {
+		for i, commit := range listing.Commits {
+			displayCommit(i+1, commit, currentUser, enableColor)
+		}
+		return
+	}
+
+	index := 1
+	for _, group := range listing.Groups {
+		fmt.Printf("\n%s (%d)\n", group.Title, group.Count)
+		for _, commit := range group.Commits {
+			displayCommit(index, commit, currentUser, enableColor)
+			index++
+		}
+	}
+}
+
+// mergePolicyNote explains how merge commits were treated in the listing
+func mergePolicyNote(listing commitListing) string {
+	switch listing.MergePolicy {
+	case picker.MergePolicySkip:
+		if listing.SkippedMerges > 0 {
+			return fmt.Sprintf("(%d merge commits skipped; use --merges mainline or expand to include them)", listing.SkippedMerges)
+		}
+	case picker.MergePolicyMainline:
+		return fmt.Sprintf("(merges are picked relative to parent %d)", listing.Mainline)
+	case picker.MergePolicyExpand:
+		return "(merges are expanded into the commits they brought in)"
+	}
+	return ""
+}
+
+// infof prints progress information. With --format json it goes to stderr so
+// stdout only carries the JSON document.
+func (p *PickCmd) infof(format string, args ...interface{}) {
+	if p.Format == formatJSON {
+		fmt.Fprintf(os.Stderr, format, args...)
+		return
+	}
+	fmt.Printf(format, args...)
+}
+
+// noCommits reports an empty result: a message in text mode, an empty listing in JSON mode.
+// An actual pick returns picker.ErrNothingToPick so the exit code tells the case apart; a
+// --show listing with nothing in it still succeeds.
+func (p *PickCmd) noCommits(message, sourceBranch, targetBranch string) error {
+	if p.Format == formatJSON {
+		if err := writeListingJSON(os.Stdout, newCommitListing(sourceBranch, targetBranch, p.GroupBy, nil)); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println(message)
+	}
+	if p.Show {
+		return nil
+	}
+	return picker.ErrNothingToPick
+}
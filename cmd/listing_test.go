@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/carlosarraes/chr/internal/git"
+	"github.com/carlosarraes/chr/internal/picker"
+)
+
+func TestNewCommitListing(t *testing.T) {
+	commits := []git.Commit{
+		{Hash: "a1", Author: "Alice", Message: "feat: add login", Date: "2024-01-02"},
+		{Hash: "b1", Author: "Bob", Message: "fix: typo", Date: "2024-01-02"},
+		{Hash: "a2", Author: "Alice", Message: "feat: add logout", Date: "2024-01-01"},
+	}
+
+	listing := newCommitListing("ZUP-1-prd", "ZUP-1-hml", "type", commits)
+	if listing.Total != 3 {
+		t.Errorf("Expected total 3, got %d", listing.Total)
+	}
+	if len(listing.Groups) != 2 {
+		t.Fatalf("Expected 2 groups, got %d", len(listing.Groups))
+	}
+	if listing.Groups[0].Title != "feat:" || listing.Groups[0].Count != 2 {
+		t.Errorf("Expected first group feat: with 2 commits, got %s with %d", listing.Groups[0].Title, listing.Groups[0].Count)
+	}
+
+	ungrouped := newCommitListing("ZUP-1-prd", "ZUP-1-hml", "", commits)
+	if len(ungrouped.Groups) != 0 {
+		t.Errorf("Expected no groups without --group-by, got %d", len(ungrouped.Groups))
+	}
+}
+
+func TestWriteListingJSON(t *testing.T) {
+	commits := []git.Commit{
+		{Hash: "a1", Author: "Alice", Message: "feat: add login", Date: "2024-01-02"},
+		{Hash: "b1", Author: "Bob", Message: "fix: typo", Date: "2024-01-01"},
+	}
+
+	var buf bytes.Buffer
+	if err := writeListingJSON(&buf, newCommitListing("ZUP-1-prd", "ZUP-1-hml", "author", commits)); err != nil {
+		t.Fatalf("writeListingJSON failed: %v", err)
+	}
+
+	var decoded commitListing
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if decoded.GroupBy != "author" || len(decoded.Groups) != 2 {
+		t.Errorf("Expected 2 author groups, got %+v", decoded.Groups)
+	}
+
+	buf.Reset()
+	if err := writeListingJSON(&buf, newCommitListing("ZUP-1-prd", "ZUP-1-hml", "", nil)); err != nil {
+		t.Fatalf("writeListingJSON failed: %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"commits": []`)) {
+		t.Errorf("Expected empty listing to contain an empty commits array, got %s", buf.String())
+	}
+}
+
+func TestPickCmd_NoCommits(t *testing.T) {
+	pick := &PickCmd{}
+	if err := pick.noCommits("Nothing here.", "ZUP-1-prd", "ZUP-1-hml"); !errors.Is(err, picker.ErrNothingToPick) {
+		t.Errorf("Expected ErrNothingToPick for a pick, got %v", err)
+	}
+
+	show := &PickCmd{Show: true}
+	if err := show.noCommits("Nothing here.", "ZUP-1-prd", "ZUP-1-hml"); err != nil {
+		t.Errorf("Expected an empty --show listing to succeed, got %v", err)
+	}
+}
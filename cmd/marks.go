@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/carlosarraes/chr/internal/config"
+	"github.com/carlosarraes/chr/internal/git"
+	"github.com/carlosarraes/chr/internal/marks"
+	"github.com/carlosarraes/chr/internal/session"
+)
+
+// IgnoreCmd represents the ignore subcommand
+type IgnoreCmd struct {
+	Commit string `kong:"arg,optional,help='Commit never to pick, e.g. a PRD-only config change'"`
+	List   bool   `kong:"short='l',help='List ignored and marked-picked commits'"`
+	Remove bool   `kong:"help='Forget the ignore or mark-picked mark on the commit'"`
+}
+
+// MarkPickedCmd represents the mark-picked subcommand
+type MarkPickedCmd struct {
+	Commit string `kong:"arg,help='Source commit already on the target branch'"`
+	As     string `kong:"help='Target commit it was picked as'"`
+}
+
+// Run executes the ignore command
+func (i *IgnoreCmd) Run(ctx context.Context, globals *CLI) error {
+	if i.List == (i.Commit != "") {
+		return fmt.Errorf("give the commit to ignore, or --list")
+	}
+	if i.List && i.Remove {
+		return fmt.Errorf("--remove needs a commit, not --list")
+	}
+
+	ctx, cfg, repoDir, err := marksSetup(ctx, globals)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case i.List:
+		return listMarks(ctx, repoDir, globals.logger)
+	case i.Remove:
+		return removeMark(ctx, repoDir, i.Commit)
+	default:
+		return saveMark(ctx, repoDir, cfg, marks.KindIgnored, i.Commit, "")
+	}
+}
+
+// Run executes the mark-picked command
+func (m *MarkPickedCmd) Run(ctx context.Context, globals *CLI) error {
+	ctx, cfg, repoDir, err := marksSetup(ctx, globals)
+	if err != nil {
+		return err
+	}
+	return saveMark(ctx, repoDir, cfg, marks.KindPicked, m.Commit, m.As)
+}
+
+// marksSetup loads the config and checks the current directory is a repository.
+// The returned context carries the configured backend.
+func marksSetup(ctx context.Context, globals *CLI) (context.Context, *config.Config, string, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to load config: %w", err)
+	}
+	ctx, err = useBackend(ctx, cfg)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	color.NoColor = globals.NoColor || !cfg.Color
+
+	repoDir, err := os.Getwd()
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to get current directory: %w", err)
+	}
+	if err := git.CheckRepository(ctx, repoDir); err != nil {
+		return nil, nil, "", err
+	}
+	return ctx, cfg, repoDir, nil
+}
+
+// saveMark records a mark on a commit, replacing any earlier one
+func saveMark(ctx context.Context, repoDir string, cfg *config.Config, kind, commit, target string) error {
+	hash, err := git.GetCommitHash(ctx, repoDir, commit)
+	if err != nil {
+		return err
+	}
+	m := marks.Mark{Commit: hash, Kind: kind, Date: time.Now()}
+	if target != "" {
+		if m.Target, err = git.GetCommitHash(ctx, repoDir, target); err != nil {
+			return err
+		}
+	}
+	// The author is only informative; marking works without it
+	m.User, _ = git.GetCurrentUser(ctx, repoDir)
+
+	if err := marks.Save(ctx, repoDir, m); err != nil {
+		return err
+	}
+
+	subject := commitSubject(ctx, repoDir, hash)
+	switch {
+	case kind == marks.KindIgnored:
+		fmt.Printf("Ignored %s %s: chr will no longer list it as unpicked.\n", session.ShortHash(hash), subject)
+	case m.Target != "":
+		fmt.Printf("Marked %s %s as picked as %s.\n", session.ShortHash(hash), subject, session.ShortHash(m.Target))
+	default:
+		fmt.Printf("Marked %s %s as picked.\n", session.ShortHash(hash), subject)
+	}
+	fmt.Printf("Share marks with your team: git push %s %s\n", cfg.Remote, marks.Ref)
+	return nil
+}
+
+// removeMark forgets the mark on a commit
+func removeMark(ctx context.Context, repoDir, commit string) error {
+	hash, err := git.GetCommitHash(ctx, repoDir, commit)
+	if err != nil {
+		return err
+	}
+	if err := marks.Remove(ctx, repoDir, hash); err != nil {
+		return err
+	}
+	fmt.Printf("Removed the mark on %s; chr matches it like any other commit again.\n", session.ShortHash(hash))
+	return nil
+}
+
+// listMarks prints every mark, oldest first
+func listMarks(ctx context.Context, repoDir string, log *slog.Logger) error {
+	all, err := marks.Load(ctx, repoDir)
+	if err != nil {
+		return err
+	}
+	if len(all) == 0 {
+		fmt.Println("No ignored or marked-picked commits.")
+		return nil
+	}
+
+	hashes := make([]string, len(all))
+	for i, m := range all {
+		hashes[i] = m.Commit
+	}
+	// Marks fetched from the remote may name commits missing here
+	commits, err := git.GetCommitsByHash(ctx, repoDir, hashes)
+	if err != nil {
+		log.Debug("failed to read marked commits", "err", err)
+	}
+
+	for _, m := range all {
+		label := m.Kind
+		if m.Target != "" {
+			label = fmt.Sprintf("%s as %s", m.Kind, session.ShortHash(m.Target))
+		}
+		date := ""
+		if !m.Date.IsZero() {
+			date = m.Date.Local().Format("2006-01-02")
+		}
+		subject := ""
+		for _, c := range commits {
+			if strings.HasPrefix(m.Commit, c.Hash) {
+				subject = c.Message
+				break
+			}
+		}
+		fmt.Printf("%s  %-18s  %-10s  %s  %s\n", color.YellowString(session.ShortHash(m.Commit)), label, date, m.User, subject)
+	}
+	return nil
+}
+
+// commitSubject returns a commit's subject, or nothing if it can't be read
+func commitSubject(ctx context.Context, repoDir, hash string) string {
+	commits, err := git.GetCommitsByHash(ctx, repoDir, []string{hash})
+	if err != nil || len(commits) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("(%s)", commits[0].Message)
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/carlosarraes/chr/internal/session"
+)
+
+func TestMarksCmds(t *testing.T) {
+	ctx := context.Background()
+	repoDir, direct, merged := setupCardWithMerge(t)
+	picked := commitFile(t, repoDir, "fix.txt", "fix\n", "fix: direct fix, picked by hand")
+
+	run := func(cmd interface {
+		Run(context.Context, *CLI) error
+	}) string {
+		t.Helper()
+		output, err := captureStdout(t, func() error { return cmd.Run(ctx, testGlobals()) })
+		if err != nil {
+			t.Fatalf("%T failed: %v", cmd, err)
+		}
+		return output
+	}
+
+	if output := run(&IgnoreCmd{Commit: merged}); !strings.Contains(output, "Ignored "+session.ShortHash(merged)) {
+		t.Errorf("Unexpected ignore output:\n%s", output)
+	}
+	if output := run(&MarkPickedCmd{Commit: direct, As: picked}); !strings.Contains(output, "as picked as "+session.ShortHash(picked)) {
+		t.Errorf("Unexpected mark-picked output:\n%s", output)
+	}
+
+	output := run(&IgnoreCmd{List: true})
+	for _, want := range []string{"ignored", "feat: add feature", "picked as " + session.ShortHash(picked), "fix: direct fix"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in the mark list, got:\n%s", want, output)
+		}
+	}
+	if output := runStatus(t); !strings.Contains(output, "Unpicked ZUP-5-prd → ZUP-5-hml: 0 commits") {
+		t.Errorf("Expected marked commits to count as handled, got:\n%s", output)
+	}
+
+	run(&IgnoreCmd{Commit: merged, Remove: true})
+	if output := runStatus(t); !strings.Contains(output, "Unpicked ZUP-5-prd → ZUP-5-hml: 1 commits") {
+		t.Errorf("Expected the unignored commit to be unpicked again, got:\n%s", output)
+	}
+
+	if err := (&IgnoreCmd{}).Run(ctx, testGlobals()); err == nil {
+		t.Error("Expected ignore without a commit or --list to fail")
+	}
+}
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/carlosarraes/chr/internal/config"
+	"github.com/carlosarraes/chr/internal/git"
+	"github.com/carlosarraes/chr/internal/notes"
+	"github.com/carlosarraes/chr/internal/session"
+)
+
+// NotesCmd represents the notes subcommand
+type NotesCmd struct {
+	Reverse bool   `kong:"short='r',help='Notes for HML to PRD instead of PRD to HML'"`
+	Session string `kong:"help='Notes for a past pick session ID (see chr status)'"`
+	Last    bool   `kong:"help='Notes for the last pick session'"`
+	Since   string `kong:"help='Notes for target branch commits since date (YYYY-MM-DD)'"`
+	Until   string `kong:"help='Notes for target branch commits until date (YYYY-MM-DD)'"`
+	Title   string `kong:"help='Override the notes title'"`
+	Output  string `kong:"short='o',help='Write the notes to a file instead of stdout'"`
+}
+
+// Run executes the notes command
+func (n *NotesCmd) Run(ctx context.Context, globals *CLI) error {
+	modes := 0
+	if n.Session != "" || n.Last {
+		modes++
+	}
+	if n.Since != "" || n.Until != "" {
+		modes++
+	}
+	if modes > 1 {
+		return fmt.Errorf("choose either a pick session (--session/--last) or a date range (--since/--until)")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	ctx, err = useBackend(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	ticketPattern, err := cfg.TicketRegexp()
+	if err != nil {
+		return err
+	}
+
+	repoDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	var commits []git.Commit
+	var title string
+
+	if n.Session != "" || n.Last {
+		commits, title, err = n.sessionCommits(ctx, repoDir)
+	} else {
+		commits, title, err = n.branchCommits(ctx, globals.logger, repoDir, cfg)
+	}
+	if err != nil {
+		return err
+	}
+
+	if n.Title != "" {
+		title = n.Title
+	}
+
+	output := notes.Render(commits, notes.Options{
+		Title:         title,
+		TicketPattern: ticketPattern,
+		TicketURL:     cfg.TicketURL,
+	})
+
+	if n.Output != "" {
+		if err := os.WriteFile(n.Output, []byte(output), 0644); err != nil {
+			return fmt.Errorf("failed to write notes: %w", err)
+		}
+		fmt.Printf("Release notes written to %s\n", n.Output)
+		return nil
+	}
+
+	fmt.Print(output)
+	return nil
+}
+
+// sessionCommits returns the commits a recorded pick session picked onto the
+// target. --last skips aborted sessions, whose picks were undone.
+func (n *NotesCmd) sessionCommits(ctx context.Context, repoDir string) ([]git.Commit, string, error) {
+	gitDir, err := git.GetGitDir(ctx, repoDir)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sessions, err := session.LoadAll(gitDir)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// A resumed or aborted session is appended again under its ID; the latest entry wins
+	var found *session.Session
+	seen := make(map[string]bool)
+	for i := len(sessions) - 1; i >= 0; i-- {
+		if seen[sessions[i].ID] {
+			continue
+		}
+		seen[sessions[i].ID] = true
+		if n.Last && sessions[i].Outcome == session.OutcomeAborted {
+			continue
+		}
+		if n.Last || sessions[i].ID == n.Session {
+			found = &sessions[i]
+			break
+		}
+	}
+	if found == nil {
+		if n.Last {
+			return nil, "", fmt.Errorf("no pick session recorded")
+		}
+		return nil, "", fmt.Errorf("pick session '%s' not found", n.Session)
+	}
+	if found.Outcome == session.OutcomeAborted {
+		return nil, "", fmt.Errorf("pick session '%s' was aborted; none of its commits are on %s", found.ID, found.TargetBranch)
+	}
+
+	picked := make(map[string]bool, len(found.Picks))
+	for _, pick := range found.Picks {
+		if pick.Status == git.PickStatusPicked {
+			picked[pick.Source] = true
+		}
+	}
+	var commits []git.Commit
+	for _, commit := range found.Commits {
+		if picked[commit.Hash] {
+			commits = append(commits, commit)
+		}
+	}
+
+	title := fmt.Sprintf("%s → %s (%s)", found.SourceBranch, found.TargetBranch, found.StartedAt.Local().Format("2006-01-02"))
+	return commits, title, nil
+}
+
+// branchCommits returns the pending commits, or the target branch commits in a date range
+func (n *NotesCmd) branchCommits(ctx context.Context, log *slog.Logger, repoDir string, cfg *config.Config) ([]git.Commit, string, error) {
+	card, err := resolveCardBranches(ctx, repoDir, cfg)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sourceBranch, targetBranch := card.Prd, card.Hml
+	if n.Reverse {
+		sourceBranch, targetBranch = card.Hml, card.Prd
+	}
+	sourceRef := git.ResolveRef(ctx, repoDir, cfg.Remote, sourceBranch, log)
+	targetRef := git.ResolveRef(ctx, repoDir, cfg.Remote, targetBranch, log)
+	mainRef := git.ResolveRef(ctx, repoDir, cfg.Remote, "main", log)
+
+	if n.Since != "" || n.Until != "" {
+		filter, err := newDateRangeFilter(n.Since, n.Until)
+		if err != nil {
+			return nil, "", err
+		}
+
+		targetCommits, err := git.GetCommits(ctx, repoDir, mainRef, targetRef, 0, log)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get target commits: %w", err)
+		}
+
+		title := fmt.Sprintf("%s (%s – %s)", targetBranch, n.Since, n.Until)
+		return git.FilterCommitsByDate(targetCommits, filter), title, nil
+	}
+
+	sourceCommits, err := git.GetCommits(ctx, repoDir, targetRef, sourceRef, 0, log)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get source commits: %w", err)
+	}
+	targetCommits, err := git.GetCommits(ctx, repoDir, mainRef, targetRef, 100, log)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get target commits: %w", err)
+	}
+
+	title := fmt.Sprintf("Pending %s → %s", sourceBranch, targetBranch)
+	matchOpts, err := matchOptions(cfg)
+	if err != nil {
+		return nil, "", err
+	}
+	unpicked, err := filterUnpicked(ctx, repoDir, matchOpts, sourceCommits, targetCommits, log)
+	if err != nil {
+		return nil, "", err
+	}
+	return excludeAlreadyApplied(ctx, repoDir, unpicked, sourceBranch, targetBranch), title, nil
+}
+
+// newDateRangeFilter builds a date filter from optional YYYY-MM-DD bounds
+func newDateRangeFilter(since, until string) (*git.DateFilter, error) {
+	filter := &git.DateFilter{Type: git.DateFilterTypeRange}
+
+	if since != "" {
+		if err := validateDate(since); err != nil {
+			return nil, fmt.Errorf("invalid since date: %w", err)
+		}
+		filter.Since, _ = time.Parse("2006-01-02", since)
+	} else {
+		filter.Type = git.DateFilterTypeUntil
+	}
+
+	if until != "" {
+		if err := validateDate(until); err != nil {
+			return nil, fmt.Errorf("invalid until date: %w", err)
+		}
+		filter.Until, _ = time.Parse("2006-01-02", until)
+	} else {
+		filter.Type = git.DateFilterTypeSince
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/carlosarraes/chr/internal/git"
+	"github.com/carlosarraes/chr/internal/session"
+)
+
+func TestNotesCmd_Last(t *testing.T) {
+	repoDir := setupRepo(t)
+	gitDir := filepath.Join(repoDir, ".git")
+
+	commits := []git.Commit{
+		{Hash: "aaa1111", Message: "feat: picked feature"},
+		{Hash: "bbb2222", Message: "fix: conflicted fix"},
+		{Hash: "ccc3333", Message: "feat: pending feature"},
+		{Hash: "ddd4444", Message: "docs: already applied docs"},
+	}
+	for _, s := range []session.Session{
+		{
+			ID:           "20240101-100000",
+			SourceBranch: "ZUP-1-prd",
+			TargetBranch: "ZUP-1-hml",
+			Commits:      commits,
+			Picks: []git.PickResult{
+				{Source: "ddd4444", Status: git.PickStatusAlreadyApplied},
+				{Source: "aaa1111", Status: git.PickStatusPicked, NewHash: "eee5555"},
+				{Source: "bbb2222", Status: git.PickStatusConflict},
+				{Source: "ccc3333", Status: git.PickStatusPending},
+			},
+			Outcome: session.OutcomeConflict,
+		},
+		{
+			ID:           "20240102-100000",
+			SourceBranch: "ZUP-1-prd",
+			TargetBranch: "ZUP-1-hml",
+			Commits:      []git.Commit{{Hash: "fff6666", Message: "feat: undone feature"}},
+			Picks:        []git.PickResult{{Source: "fff6666", Status: git.PickStatusPicked, NewHash: "ggg7777"}},
+			Outcome:      session.OutcomeAborted,
+		},
+	} {
+		if err := session.Append(gitDir, s); err != nil {
+			t.Fatalf("Failed to record session: %v", err)
+		}
+	}
+
+	output, err := captureStdout(t, func() error {
+		return (&NotesCmd{Last: true}).Run(context.Background(), testGlobals())
+	})
+	if err != nil {
+		t.Fatalf("notes --last failed: %v", err)
+	}
+	if !strings.Contains(output, "picked feature") {
+		t.Errorf("Expected the picked commit in the notes, got:\n%s", output)
+	}
+	for _, skipped := range []string{"conflicted fix", "pending feature", "already applied docs", "undone feature"} {
+		if strings.Contains(output, skipped) {
+			t.Errorf("Expected %q to be left out of the notes, got:\n%s", skipped, output)
+		}
+	}
+
+	_, err = captureStdout(t, func() error {
+		return (&NotesCmd{Session: "20240102-100000"}).Run(context.Background(), testGlobals())
+	})
+	if err == nil || !strings.Contains(err.Error(), "aborted") {
+		t.Errorf("Expected notes for an aborted session to fail, got %v", err)
+	}
+}
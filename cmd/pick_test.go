@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/carlosarraes/chr/internal/git"
+	"github.com/carlosarraes/chr/internal/session"
+)
+
+// setupRepo creates a repository with a main branch and makes it the working
+// directory, with HOME pointed away from the user's chr config
+func setupRepo(t *testing.T) string {
+	t.Helper()
+	repoDir := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+
+	runGit(t, repoDir, "init", "-b", "main")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	commitFile(t, repoDir, "README.md", "chr\n", "chore: base commit")
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(repoDir); err != nil {
+		t.Fatalf("Failed to enter repo: %v", err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	return repoDir
+}
+
+// commitFile writes name and commits it, returning the new commit hash
+func commitFile(t *testing.T, repoDir, name, content, message string) string {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(repoDir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	runGit(t, repoDir, "add", name)
+	runGit(t, repoDir, "commit", "-m", message)
+	return gitOutput(t, repoDir, "rev-parse", "HEAD")
+}
+
+func runGit(t *testing.T, repoDir string, args ...string) {
+	t.Helper()
+	gitOutput(t, repoDir, args...)
+}
+
+func gitOutput(t *testing.T, repoDir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// captureStdout returns what fn prints to standard output, colored or not
+func captureStdout(t *testing.T, fn func() error) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	stdout, colorOutput := os.Stdout, color.Output
+	os.Stdout, color.Output = w, w
+	defer func() { os.Stdout, color.Output = stdout, colorOutput }()
+
+	done := make(chan []byte)
+	go func() {
+		output, _ := io.ReadAll(r)
+		done <- output
+	}()
+	fnErr := fn()
+	w.Close()
+	return string(<-done), fnErr
+}
+
+func testGlobals() *CLI {
+	return &CLI{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+}
+
+func testPickCmd() *PickCmd {
+	return &PickCmd{log: slog.New(slog.NewTextHandler(io.Discard, nil))}
+}
+
+func TestHandleContinue_AfterGitContinue(t *testing.T) {
+	ctx := context.Background()
+	repoDir := setupRepo(t)
+
+	runGit(t, repoDir, "checkout", "-b", "ZUP-1-prd")
+	conflicting := commitFile(t, repoDir, "app.txt", "prd\n", "feat: change app")
+	pending := commitFile(t, repoDir, "other.txt", "other\n", "feat: add other")
+
+	runGit(t, repoDir, "checkout", "-b", "ZUP-1-hml", "main")
+	startHead := commitFile(t, repoDir, "app.txt", "hml\n", "feat: hml app")
+
+	commits := []git.Commit{{Hash: conflicting}, {Hash: pending}}
+	results, pickErr := git.CherryPickCommits(ctx, repoDir, commits, git.PickOptions{})
+	if !git.IsPickStopped(pickErr) {
+		t.Fatalf("Expected a conflict, got %v", pickErr)
+	}
+	gitDir := filepath.Join(repoDir, ".git")
+	if err := session.Append(gitDir, session.Session{
+		ID:           session.NewID(time.Now()),
+		SourceBranch: "ZUP-1-prd",
+		TargetBranch: "ZUP-1-hml",
+		StartHead:    startHead,
+		Commits:      commits,
+		Picks:        results,
+		Outcome:      session.OutcomeConflict,
+	}); err != nil {
+		t.Fatalf("Failed to record session: %v", err)
+	}
+
+	// Resolved and committed with git alone, so nothing is in progress any more
+	if err := os.WriteFile(filepath.Join(repoDir, "app.txt"), []byte("resolved\n"), 0644); err != nil {
+		t.Fatalf("Failed to resolve conflict: %v", err)
+	}
+	runGit(t, repoDir, "add", "app.txt")
+	runGit(t, repoDir, "-c", "core.editor=true", "cherry-pick", "--continue")
+	resolved := gitOutput(t, repoDir, "rev-parse", "HEAD")
+
+	if err := testPickCmd().handleContinue(ctx); err != nil {
+		t.Fatalf("handleContinue failed: %v", err)
+	}
+
+	last, err := session.Last(gitDir)
+	if err != nil || last == nil {
+		t.Fatalf("Failed to load the last session: %v", err)
+	}
+	if last.Outcome != session.OutcomeSuccess {
+		t.Errorf("Expected outcome success, got %s", last.Outcome)
+	}
+	if len(last.Picks) != 2 || last.Picks[0].Status != git.PickStatusPicked || last.Picks[0].NewHash != resolved {
+		t.Fatalf("Expected the resolved commit %s to count as picked, got %+v", resolved, last.Picks)
+	}
+	if last.Picks[1].Status != git.PickStatusPicked || last.Picks[1].NewHash != gitOutput(t, repoDir, "rev-parse", "HEAD") {
+		t.Errorf("Expected the pending commit to be picked onto HEAD, got %+v", last.Picks[1])
+	}
+}
+
+func TestHandleContinue_AfterFailure(t *testing.T) {
+	ctx := context.Background()
+	repoDir := setupRepo(t)
+
+	runGit(t, repoDir, "checkout", "-b", "ZUP-2-prd")
+	source := commitFile(t, repoDir, "app.txt", "prd\n", "feat: change app")
+	runGit(t, repoDir, "checkout", "-b", "ZUP-2-hml", "main")
+	startHead := gitOutput(t, repoDir, "rev-parse", "HEAD")
+
+	gitDir := filepath.Join(repoDir, ".git")
+	reportPath := filepath.Join(t.TempDir(), "report.md")
+	failed := session.Session{
+		ID:           session.NewID(time.Now()),
+		SourceBranch: "ZUP-2-prd",
+		TargetBranch: "ZUP-2-hml",
+		StartHead:    startHead,
+		Commits:      []git.Commit{{Hash: source}},
+		Picks:        []git.PickResult{{Source: source, Status: git.PickStatusPending}},
+		Outcome:      session.OutcomeFailed,
+		Report:       reportPath,
+	}
+	if err := session.Append(gitDir, failed); err != nil {
+		t.Fatalf("Failed to record session: %v", err)
+	}
+
+	if err := testPickCmd().handleContinue(ctx); err != nil {
+		t.Fatalf("handleContinue failed: %v", err)
+	}
+
+	last, err := session.Last(gitDir)
+	if err != nil || last == nil {
+		t.Fatalf("Failed to load the last session: %v", err)
+	}
+	if last.Outcome != session.OutcomeSuccess || len(last.Picks) != 1 || last.Picks[0].Status != git.PickStatusPicked {
+		t.Errorf("Expected the failed commit to be picked, got %s %+v", last.Outcome, last.Picks)
+	}
+	// --report was given when the pick started, not with --continue
+	if _, err := os.Stat(reportPath); err != nil {
+		t.Errorf("Expected the report the pick started with to be written: %v", err)
+	}
+}
+
+func TestPickCmd_RequiresTargetCheckedOut(t *testing.T) {
+	repoDir := setupRepo(t)
+	runGit(t, repoDir, "branch", "ZUP-3-hml")
+	runGit(t, repoDir, "checkout", "-b", "ZUP-3-prd")
+	commitFile(t, repoDir, "app.txt", "prd\n", "feat: change app")
+
+	err := (&PickCmd{Count: 5}).Run(context.Background(), testGlobals())
+	if err == nil || !strings.Contains(err.Error(), "switch to ZUP-3-hml") {
+		t.Fatalf("Expected the pick to ask for ZUP-3-hml, got %v", err)
+	}
+	if head := gitOutput(t, repoDir, "log", "-1", "--format=%s"); head != "feat: change app" {
+		t.Errorf("Expected ZUP-3-prd to be left alone, got HEAD %q", head)
+	}
+}
+
+func TestPickCmd_FiltersExpandedMerges(t *testing.T) {
+	repoDir := setupRepo(t)
+	runGit(t, repoDir, "branch", "ZUP-4-hml")
+	// Someone else's branch, merged by the current user
+	runGit(t, repoDir, "checkout", "-b", "feature")
+	runGit(t, repoDir, "config", "user.name", "Bob")
+	feat := commitFile(t, repoDir, "a.txt", "a\n", "feat: add a")
+	commitFile(t, repoDir, "b.txt", "b\n", "chore: tidy b")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+	runGit(t, repoDir, "checkout", "-b", "ZUP-4-prd", "main")
+	runGit(t, repoDir, "merge", "--no-ff", "feature", "-m", "Merge branch 'feature'")
+	runGit(t, repoDir, "checkout", "ZUP-4-hml")
+
+	pick := &PickCmd{Show: true, Format: formatJSON, Merges: "expand", Type: []string{"feat"}, Count: 5}
+	output, err := captureStdout(t, func() error { return pick.Run(context.Background(), testGlobals()) })
+	if err != nil {
+		t.Fatalf("pick --show failed: %v", err)
+	}
+
+	start := strings.Index(output, "{")
+	if start < 0 {
+		t.Fatalf("Expected a JSON listing, got:\n%s", output)
+	}
+	var listing commitListing
+	if err := json.Unmarshal([]byte(output[start:]), &listing); err != nil {
+		t.Fatalf("Output is not a JSON listing: %v\n%s", err, output)
+	}
+	if len(listing.Commits) != 1 || !strings.HasPrefix(feat, listing.Commits[0].Hash) {
+		t.Errorf("Expected only the feat commit the merge brought in, got %+v", listing.Commits)
+	}
+}
@@ -6,5 +6,5 @@ import (
 )
 
 func Execute(ctx context.Context) error {
-	return ExecuteCLI(os.Args[1:])
+	return ExecuteCLI(ctx, os.Args[1:])
 }
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"testing"
 )
 
@@ -20,7 +21,7 @@ func TestExecuteCLI_Help(t *testing.T) {
 
 func TestExecuteCLI_InvalidArgs(t *testing.T) {
 	args := []string{"--invalid-flag"}
-	err := ExecuteCLI(args)
+	err := ExecuteCLI(context.Background(), args)
 
 	if err == nil {
 		t.Error("Expected error for invalid flag")
@@ -116,6 +117,10 @@ func TestValidateConfigValue(t *testing.T) {
 		{"prefix", "", true},
 		{"suffix_prd", "-prod", false},
 		{"suffix_prd", "", true},
+		{"match_strategies", "exact,patch_id", false},
+		{"match_strategies", "exact,nope", true},
+		{"match_min_score", "60", false},
+		{"match_min_score", "101", true},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/fatih/color"
+
+	"github.com/carlosarraes/chr/internal/git"
+	"github.com/carlosarraes/chr/internal/picker"
+	"github.com/carlosarraes/chr/internal/session"
+)
+
+// StatusCmd represents the status subcommand
+type StatusCmd struct {
+	Fetch   bool `kong:"help='Fetch the card branches from the remote first'"`
+	Reverse bool `kong:"short='r',help='Report unpicked commits from HML to PRD instead of PRD to HML'"`
+}
+
+// Run executes the status command
+func (s *StatusCmd) Run(ctx context.Context, globals *CLI) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	ctx, err = useBackend(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	color.NoColor = globals.NoColor || !cfg.Color
+
+	repoDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	card, err := resolveCardBranches(ctx, repoDir, cfg)
+	if err != nil {
+		return err
+	}
+
+	if s.Fetch {
+		if err := git.FetchBranches(ctx, repoDir, cfg.Remote, globals.logger, card.Prd, card.Hml); err != nil {
+			return fmt.Errorf("failed to fetch branches: %w", err)
+		}
+	}
+
+	headerColor := color.New(color.Bold)
+
+	headerColor.Printf("Card %s%s\n", cfg.Prefix, card.Card)
+	fmt.Printf("Current branch: %s\n", card.Current)
+
+	// Local vs remote state of each branch
+	for _, branch := range []string{card.Prd, card.Hml} {
+		status, err := git.GetSyncStatus(ctx, repoDir, cfg.Remote, branch)
+		if err != nil {
+			return fmt.Errorf("failed to compare %s with its remote: %w", branch, err)
+		}
+		fmt.Printf("  %s: %s\n", branch, describeSync(status))
+	}
+
+	prdRef := git.ResolveRef(ctx, repoDir, cfg.Remote, card.Prd, globals.logger)
+	hmlRef := git.ResolveRef(ctx, repoDir, cfg.Remote, card.Hml, globals.logger)
+
+	// PRD vs HML
+	prdOnly, hmlOnly, err := git.GetAheadBehind(ctx, repoDir, prdRef, hmlRef)
+	if err != nil {
+		return fmt.Errorf("failed to compare PRD and HML: %w", err)
+	}
+	fmt.Printf("\nPRD vs HML: %d commits only in %s, %d only in %s\n", prdOnly, prdRef, hmlOnly, hmlRef)
+
+	// Unpicked commits per author
+	sourceRef, targetRef := prdRef, hmlRef
+	sourceBranch, targetBranch := card.Prd, card.Hml
+	if s.Reverse {
+		sourceRef, targetRef = hmlRef, prdRef
+		sourceBranch, targetBranch = card.Hml, card.Prd
+	}
+
+	sourceCommits, err := git.GetCommits(ctx, repoDir, targetRef, sourceRef, 0, globals.logger)
+	if err != nil {
+		return fmt.Errorf("failed to get source commits: %w", err)
+	}
+	// Count what chr pick would pick, not the merges it skips or expands
+	if err := ValidateConfigValue("merge_policy", cfg.MergePolicy); err != nil {
+		return err
+	}
+	sourceCommits, _, err = applyMergePolicy(ctx, repoDir, cfg.MergePolicy, sourceCommits, targetRef, sourceRef)
+	if err != nil {
+		return err
+	}
+	if cfg.MergePolicy == picker.MergePolicyExpand {
+		if sourceCommits, err = expandMerges(ctx, repoDir, sourceCommits, sourceCommits); err != nil {
+			return err
+		}
+	}
+	targetCommits, err := git.GetCommits(ctx, repoDir, git.ResolveRef(ctx, repoDir, cfg.Remote, "main", globals.logger), targetRef, 100, globals.logger)
+	if err != nil {
+		return fmt.Errorf("failed to get target commits: %w", err)
+	}
+
+	matchOpts, err := matchOptions(cfg)
+	if err != nil {
+		return err
+	}
+	unpicked, err := filterUnpicked(ctx, repoDir, matchOpts, sourceCommits, targetCommits, globals.logger)
+	if err != nil {
+		return err
+	}
+	unpicked = excludeAlreadyApplied(ctx, repoDir, unpicked, sourceBranch, targetBranch)
+	summary := picker.SummarizeCommits(unpicked)
+
+	fmt.Println()
+	headerColor.Printf("Unpicked %s → %s: %d commits\n", sourceRef, targetRef, summary.Total)
+	authors := make([]string, 0, len(summary.ByAuthor))
+	for author := range summary.ByAuthor {
+		authors = append(authors, author)
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		if summary.ByAuthor[authors[i]] != summary.ByAuthor[authors[j]] {
+			return summary.ByAuthor[authors[i]] > summary.ByAuthor[authors[j]]
+		}
+		return authors[i] < authors[j]
+	})
+	for _, author := range authors {
+		fmt.Printf("  %s: %d\n", author, summary.ByAuthor[author])
+	}
+
+	// Operation in progress
+	operation, err := git.GetOperationInProgress(ctx, repoDir)
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+	switch operation {
+	case git.OperationCherryPick:
+		color.New(color.FgYellow).Println("In progress: cherry-pick (resolve conflicts, then chr pick --continue)")
+	case git.OperationRebase:
+		color.New(color.FgYellow).Println("In progress: rebase (git rebase --continue or --abort)")
+	case git.OperationMerge:
+		color.New(color.FgYellow).Println("In progress: merge (commit or git merge --abort)")
+	default:
+		fmt.Println("In progress: nothing")
+	}
+
+	// Last pick session
+	gitDir, err := git.GetGitDir(ctx, repoDir)
+	if err != nil {
+		return err
+	}
+	last, err := session.Last(gitDir)
+	if err != nil {
+		return err
+	}
+	if last == nil {
+		fmt.Println("Last pick session: none recorded")
+		return nil
+	}
+	fmt.Printf("Last pick session: %s (%s) %s → %s, %d commits, %s\n",
+		last.ID, last.StartedAt.Local().Format("2006-01-02 15:04"),
+		last.SourceBranch, last.TargetBranch, len(last.Commits), last.Outcome)
+
+	return nil
+}
+
+// describeSync renders a branch's local/remote relationship for the status output
+func describeSync(status git.SyncStatus) string {
+	switch {
+	case !status.HasLocal && !status.HasRemote:
+		return "missing locally and on the remote"
+	case !status.HasLocal:
+		return fmt.Sprintf("only on the remote (%s)", status.RemoteRef)
+	case !status.HasRemote:
+		return fmt.Sprintf("local only (no %s)", status.RemoteRef)
+	case status.Diverged():
+		return fmt.Sprintf("diverged from %s (%d ahead, %d behind)", status.RemoteRef, status.Ahead, status.Behind)
+	case status.Ahead > 0:
+		return fmt.Sprintf("%d ahead of %s", status.Ahead, status.RemoteRef)
+	case status.Behind > 0:
+		return fmt.Sprintf("%d behind %s", status.Behind, status.RemoteRef)
+	default:
+		return fmt.Sprintf("in sync with %s", status.RemoteRef)
+	}
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// setupCardWithMerge builds ZUP-5-prd with a direct commit and a merged feature
+// branch, and checks out an empty ZUP-5-hml
+func setupCardWithMerge(t *testing.T) (repoDir, direct, merged string) {
+	t.Helper()
+	repoDir = setupRepo(t)
+	runGit(t, repoDir, "branch", "ZUP-5-hml")
+	runGit(t, repoDir, "checkout", "-b", "feature")
+	merged = commitFile(t, repoDir, "feature.txt", "feature\n", "feat: add feature")
+	runGit(t, repoDir, "checkout", "-b", "ZUP-5-prd", "main")
+	direct = commitFile(t, repoDir, "fix.txt", "fix\n", "fix: direct fix")
+	runGit(t, repoDir, "merge", "--no-ff", "feature", "-m", "Merge branch 'feature'")
+	runGit(t, repoDir, "checkout", "ZUP-5-hml")
+	return repoDir, direct, merged
+}
+
+func runStatus(t *testing.T) string {
+	t.Helper()
+	output, err := captureStdout(t, func() error {
+		return (&StatusCmd{}).Run(context.Background(), testGlobals())
+	})
+	if err != nil {
+		t.Fatalf("status failed: %v", err)
+	}
+	return output
+}
+
+func TestStatusCmd_MergePolicy(t *testing.T) {
+	setupCardWithMerge(t)
+
+	// The default policy skips the merge, leaving the two commits pick would list
+	if output := runStatus(t); !strings.Contains(output, "Unpicked ZUP-5-prd → ZUP-5-hml: 2 commits") {
+		t.Errorf("Expected 2 unpicked commits with merges skipped, got:\n%s", output)
+	}
+
+	// mainline keeps the first-parent history: the direct commit and the merge
+	t.Setenv("CHR_MERGE_POLICY", "mainline")
+	output := runStatus(t)
+	if !strings.Contains(output, "Unpicked ZUP-5-prd → ZUP-5-hml: 2 commits") {
+		t.Errorf("Expected 2 unpicked commits on the first-parent history, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Last pick session: none recorded") {
+		t.Errorf("Expected no pick session, got:\n%s", output)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -19,16 +20,84 @@ const (
 	DefaultSuffixPrd = "-prd"
 	DefaultSuffixHml = "-hml"
 	DefaultColor     = true
+
+	DefaultPushAfterPick = false
+	DefaultRemote        = "origin"
+
+	// DefaultTicketPattern matches ticket IDs made of the card prefix and a number
+	DefaultTicketPattern = "{prefix}[0-9]+"
+
+	DefaultForge = "github"
+
+	DefaultMergePolicy   = "skip"
+	DefaultMergeMainline = 1
+	DefaultEmptyPolicy   = "skip"
+
+	DefaultBackend = "git"
+
+	DefaultMatchMinScore = 0
+
+	DefaultNotifyTimeoutSeconds = 5
+	DefaultNotifyRetries        = 2
 )
 
+// DefaultMatchStrategies are the commit matching strategies used unless configured
+var DefaultMatchStrategies = []string{"exact", "author_subject"}
+
 type Config struct {
 	Prefix    string `koanf:"prefix"`
 	SuffixPrd string `koanf:"suffix_prd"`
 	SuffixHml string `koanf:"suffix_hml"`
 	Color     bool   `koanf:"color"`
+
+	PushAfterPick bool   `koanf:"push_after_pick"`
+	Remote        string `koanf:"remote"`
+	TicketPattern string `koanf:"ticket_pattern"`
+	TicketURL     string `koanf:"ticket_url"`
+
+	Forge     string `koanf:"forge"`
+	ForgeURL  string `koanf:"forge_url"`
+	ForgeRepo string `koanf:"forge_repo"`
+
+	JiraURL        string `koanf:"jira_url"`
+	JiraUser       string `koanf:"jira_user"`
+	JiraToken      string `koanf:"jira_token"`
+	JiraComment    bool   `koanf:"jira_comment"`
+	JiraTransition string `koanf:"jira_transition"`
+
+	MergePolicy   string `koanf:"merge_policy"`
+	MergeMainline int    `koanf:"merge_mainline"`
+	EmptyPolicy   string `koanf:"empty_policy"`
+
+	Backend string `koanf:"backend"`
+
+	MatchStrategies []string `koanf:"match_strategies"`
+	MatchMinScore   int      `koanf:"match_min_score"`
+
+	Notify NotifyConfig `koanf:"notify"`
+}
+
+// NotifyConfig holds the [notify] section: webhooks told about every pick session
+type NotifyConfig struct {
+	Webhooks       []string `koanf:"webhooks"`
+	TimeoutSeconds int      `koanf:"timeout_seconds"`
+	Retries        int      `koanf:"retries"`
 }
 
+// LoadConfig reads the defaults, the config file and the CHR_ environment
+// variables, later sources overriding earlier ones
 func LoadConfig(configPath string) (*Config, error) {
+	return load(configPath, true)
+}
+
+// LoadFileConfig reads the defaults and the config file only. Use it for a
+// config that is written back, so values from the environment (such as
+// CHR_JIRA_TOKEN) never end up in the file.
+func LoadFileConfig(configPath string) (*Config, error) {
+	return load(configPath, false)
+}
+
+func load(configPath string, withEnv bool) (*Config, error) {
 	k := koanf.New(".")
 
 	// Load default values
@@ -37,6 +106,26 @@ func LoadConfig(configPath string) (*Config, error) {
 		SuffixPrd: DefaultSuffixPrd,
 		SuffixHml: DefaultSuffixHml,
 		Color:     DefaultColor,
+
+		PushAfterPick: DefaultPushAfterPick,
+		Remote:        DefaultRemote,
+		TicketPattern: DefaultTicketPattern,
+
+		Forge: DefaultForge,
+
+		MergePolicy:   DefaultMergePolicy,
+		MergeMainline: DefaultMergeMainline,
+		EmptyPolicy:   DefaultEmptyPolicy,
+
+		Backend: DefaultBackend,
+
+		MatchStrategies: DefaultMatchStrategies,
+		MatchMinScore:   DefaultMatchMinScore,
+
+		Notify: NotifyConfig{
+			TimeoutSeconds: DefaultNotifyTimeoutSeconds,
+			Retries:        DefaultNotifyRetries,
+		},
 	}
 
 	if err := k.Load(structs.Provider(defaultCfg, "koanf"), nil); err != nil {
@@ -55,16 +144,20 @@ func LoadConfig(configPath string) (*Config, error) {
 	}
 
 	// Load from environment variables (CHR_PREFIX, CHR_SUFFIX_PRD, etc.)
-	if err := k.Load(env.Provider("CHR_", ".", func(s string) string {
-		return strings.ToLower(strings.TrimPrefix(s, "CHR_"))
-	}), nil); err != nil {
-		return nil, fmt.Errorf("failed to load environment variables: %w", err)
+	if withEnv {
+		if err := k.Load(env.Provider("CHR_", ".", func(s string) string {
+			return strings.ToLower(strings.TrimPrefix(s, "CHR_"))
+		}), nil); err != nil {
+			return nil, fmt.Errorf("failed to load environment variables: %w", err)
+		}
 	}
 
 	var cfg Config
 	if err := k.Unmarshal("", &cfg); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
+	// CHR_MATCH_STRATEGIES="exact,trailer" arrives as a single item
+	cfg.MatchStrategies = splitList(strings.Join(cfg.MatchStrategies, ","))
 
 	return &cfg, nil
 }
@@ -95,20 +188,133 @@ suffix_hml = "%s"
 
 # Enable colored output (default: %v)
 color = %v
+
+# Push the target branch after a successful pick (default: %v)
+push_after_pick = %v
+
+# The remote used to fetch and push card branches (default: "%s")
+remote = "%s"
+
+# Regular expression for ticket IDs; {prefix} is replaced by the prefix (default: "%s")
+ticket_pattern = %q
+
+# Link template for tickets in release notes, e.g. "https://jira.example.com/browse/{ticket}"
+ticket_url = %q
+
+# Where chr pick --pr opens pull/merge requests: "github" or "gitlab" (default: "%s")
+forge = "%s"
+
+# API base URL of the forge; empty uses the public GitHub/GitLab API
+forge_url = %q
+
+# Repository ("owner/name" or GitLab project path); empty derives it from the remote URL
+forge_repo = %q
+
+# Jira base URL, e.g. "https://acme.atlassian.net"
+jira_url = %q
+
+# Jira user (email for Jira Cloud); empty sends jira_token as a bearer token
+jira_user = %q
+
+# Jira API token; prefer the CHR_JIRA_TOKEN environment variable
+jira_token = %q
+
+# Comment on the card's Jira issue after a successful pick (default: false)
+jira_comment = %v
+
+# Move the card's Jira issue to this status after a successful pick (default: empty, no move)
+jira_transition = %q
+
+# Merge commits in the source branch: "skip", "mainline" (pick with -m) or "expand" (default: "%s")
+merge_policy = "%s"
+
+# Parent number merges are picked relative to with merge_policy = "mainline" (default: %d)
+merge_mainline = %d
+
+# Commits already applied on the target: "skip", "keep" (as empty commits) or "stop" (default: "%s")
+empty_policy = "%s"
+
+# How read-only queries (refs, logs, user) are answered: "git" runs the git CLI,
+# "go-git" reads the repository in-process (default: "%s")
+backend = "%s"
+
+# How commits already picked to the target are recognized, in priority order:
+# "exact" (author, date and subject), "author_subject", "patch_id" (same diff),
+# "fuzzy" (similar subject) and "trailer" (git cherry-pick -x line) (default: %s)
+match_strategies = %s
+
+# Lowest match score (0-100) that counts a commit as picked (default: %d)
+match_min_score = %d
+
+[notify]
+# Webhook URLs receiving a JSON payload after each pick or conflict
+webhooks = %s
+
+# Per-request timeout in seconds (default: %d)
+timeout_seconds = %d
+
+# Retries for failed deliveries (default: %d)
+retries = %d
 `,
 		DefaultPrefix, cfg.Prefix,
 		DefaultSuffixPrd, cfg.SuffixPrd,
 		DefaultSuffixHml, cfg.SuffixHml,
 		DefaultColor, cfg.Color,
+		DefaultPushAfterPick, cfg.PushAfterPick,
+		DefaultRemote, cfg.Remote,
+		DefaultTicketPattern, cfg.TicketPattern,
+		cfg.TicketURL,
+		DefaultForge, cfg.Forge,
+		cfg.ForgeURL,
+		cfg.ForgeRepo,
+		cfg.JiraURL,
+		cfg.JiraUser,
+		cfg.JiraToken,
+		cfg.JiraComment,
+		cfg.JiraTransition,
+		DefaultMergePolicy, cfg.MergePolicy,
+		DefaultMergeMainline, cfg.MergeMainline,
+		DefaultEmptyPolicy, cfg.EmptyPolicy,
+		DefaultBackend, cfg.Backend,
+		tomlStringArray(DefaultMatchStrategies), tomlStringArray(cfg.MatchStrategies),
+		DefaultMatchMinScore, cfg.MatchMinScore,
+		tomlStringArray(cfg.Notify.Webhooks),
+		DefaultNotifyTimeoutSeconds, cfg.Notify.TimeoutSeconds,
+		DefaultNotifyRetries, cfg.Notify.Retries,
 	)
 
-	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+	// The file may hold jira_token, so keep it private to the user
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
+	// WriteFile keeps the mode of an existing file
+	if err := os.Chmod(configPath, 0600); err != nil {
+		return fmt.Errorf("failed to restrict config file permissions: %w", err)
+	}
 
 	return nil
 }
 
+// splitList splits a comma-separated value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// tomlStringArray renders a string slice as a TOML array
+func tomlStringArray(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = strconv.Quote(value)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
 func GetConfigPath() string {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
@@ -131,6 +337,72 @@ func (c *Config) Set(key, value string) error {
 			return fmt.Errorf("invalid boolean value for color: %s", value)
 		}
 		c.Color = boolVal
+	case "push_after_pick":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean value for push_after_pick: %s", value)
+		}
+		c.PushAfterPick = boolVal
+	case "remote":
+		c.Remote = value
+	case "ticket_pattern":
+		c.TicketPattern = value
+	case "ticket_url":
+		c.TicketURL = value
+	case "forge":
+		c.Forge = value
+	case "forge_url":
+		c.ForgeURL = value
+	case "forge_repo":
+		c.ForgeRepo = value
+	case "jira_url":
+		c.JiraURL = value
+	case "jira_user":
+		c.JiraUser = value
+	case "jira_token":
+		c.JiraToken = value
+	case "jira_comment":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean value for jira_comment: %s", value)
+		}
+		c.JiraComment = boolVal
+	case "jira_transition":
+		c.JiraTransition = value
+	case "merge_policy":
+		c.MergePolicy = value
+	case "merge_mainline":
+		intVal, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer value for merge_mainline: %s", value)
+		}
+		c.MergeMainline = intVal
+	case "empty_policy":
+		c.EmptyPolicy = value
+	case "backend":
+		c.Backend = value
+	case "match_strategies":
+		c.MatchStrategies = splitList(value)
+	case "match_min_score":
+		intVal, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer value for match_min_score: %s", value)
+		}
+		c.MatchMinScore = intVal
+	case "notify.webhooks":
+		c.Notify.Webhooks = splitList(value)
+	case "notify.timeout_seconds":
+		intVal, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer value for notify.timeout_seconds: %s", value)
+		}
+		c.Notify.TimeoutSeconds = intVal
+	case "notify.retries":
+		intVal, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer value for notify.retries: %s", value)
+		}
+		c.Notify.Retries = intVal
 	default:
 		return fmt.Errorf("unknown configuration key: %s", key)
 	}
@@ -147,6 +419,48 @@ func (c *Config) Get(key string) (string, error) {
 		return c.SuffixHml, nil
 	case "color":
 		return strconv.FormatBool(c.Color), nil
+	case "push_after_pick":
+		return strconv.FormatBool(c.PushAfterPick), nil
+	case "remote":
+		return c.Remote, nil
+	case "ticket_pattern":
+		return c.TicketPattern, nil
+	case "ticket_url":
+		return c.TicketURL, nil
+	case "forge":
+		return c.Forge, nil
+	case "forge_url":
+		return c.ForgeURL, nil
+	case "forge_repo":
+		return c.ForgeRepo, nil
+	case "jira_url":
+		return c.JiraURL, nil
+	case "jira_user":
+		return c.JiraUser, nil
+	case "jira_token":
+		return c.JiraToken, nil
+	case "jira_comment":
+		return strconv.FormatBool(c.JiraComment), nil
+	case "jira_transition":
+		return c.JiraTransition, nil
+	case "merge_policy":
+		return c.MergePolicy, nil
+	case "merge_mainline":
+		return strconv.Itoa(c.MergeMainline), nil
+	case "empty_policy":
+		return c.EmptyPolicy, nil
+	case "backend":
+		return c.Backend, nil
+	case "match_strategies":
+		return strings.Join(c.MatchStrategies, ","), nil
+	case "match_min_score":
+		return strconv.Itoa(c.MatchMinScore), nil
+	case "notify.webhooks":
+		return strings.Join(c.Notify.Webhooks, ","), nil
+	case "notify.timeout_seconds":
+		return strconv.Itoa(c.Notify.TimeoutSeconds), nil
+	case "notify.retries":
+		return strconv.Itoa(c.Notify.Retries), nil
 	default:
 		return "", fmt.Errorf("unknown configuration key: %s", key)
 	}
@@ -157,5 +471,56 @@ func (c *Config) String() string {
   prefix: %s
   suffix_prd: %s
   suffix_hml: %s
-  color: %v`, c.Prefix, c.SuffixPrd, c.SuffixHml, c.Color)
+  color: %v
+  push_after_pick: %v
+  remote: %s
+  ticket_pattern: %s
+  ticket_url: %s
+  forge: %s
+  forge_url: %s
+  forge_repo: %s
+  jira_url: %s
+  jira_user: %s
+  jira_token: %s
+  jira_comment: %v
+  jira_transition: %s
+  merge_policy: %s
+  merge_mainline: %d
+  empty_policy: %s
+  backend: %s
+  match_strategies: %s
+  match_min_score: %d
+  notify.webhooks: %s
+  notify.timeout_seconds: %d
+  notify.retries: %d`, c.Prefix, c.SuffixPrd, c.SuffixHml, c.Color, c.PushAfterPick, c.Remote, c.TicketPattern, c.TicketURL,
+		c.Forge, c.ForgeURL, c.ForgeRepo,
+		c.JiraURL, c.JiraUser, maskSecret(c.JiraToken), c.JiraComment, c.JiraTransition,
+		c.MergePolicy, c.MergeMainline, c.EmptyPolicy, c.Backend,
+		strings.Join(c.MatchStrategies, ","), c.MatchMinScore,
+		strings.Join(c.Notify.Webhooks, ","), c.Notify.TimeoutSeconds, c.Notify.Retries)
+}
+
+// maskSecret hides a credential when printing the configuration
+func maskSecret(value string) string {
+	if value == "" {
+		return ""
+	}
+	return "(set)"
+}
+
+// TicketRegexp compiles the ticket pattern, substituting {prefix} with the
+// quoted card prefix. An empty pattern falls back to the default. Tickets only
+// match as whole words, so ZUP-45 is not found inside ZUP-456.
+func (c *Config) TicketRegexp() (*regexp.Regexp, error) {
+	pattern := c.TicketPattern
+	if pattern == "" {
+		pattern = DefaultTicketPattern
+	}
+	pattern = strings.ReplaceAll(pattern, "{prefix}", regexp.QuoteMeta(c.Prefix))
+
+	re, err := regexp.Compile(`\b(?:` + pattern + `)\b`)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ticket_pattern %q: %w", c.TicketPattern, err)
+	}
+	return re, nil
 }
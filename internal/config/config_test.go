@@ -28,6 +28,27 @@ func TestLoadConfig_Default(t *testing.T) {
 	if !cfg.Color {
 		t.Error("Expected color to be true by default")
 	}
+	if cfg.PushAfterPick {
+		t.Error("Expected push_after_pick to be false by default")
+	}
+	if cfg.Remote != DefaultRemote {
+		t.Errorf("Expected remote %q, got %q", DefaultRemote, cfg.Remote)
+	}
+	if cfg.Forge != DefaultForge {
+		t.Errorf("Expected forge %q, got %q", DefaultForge, cfg.Forge)
+	}
+	if cfg.MergePolicy != DefaultMergePolicy || cfg.MergeMainline != DefaultMergeMainline {
+		t.Errorf("Expected merge defaults, got %q/%d", cfg.MergePolicy, cfg.MergeMainline)
+	}
+	if cfg.EmptyPolicy != DefaultEmptyPolicy {
+		t.Errorf("Expected empty_policy %q, got %q", DefaultEmptyPolicy, cfg.EmptyPolicy)
+	}
+	if strings.Join(cfg.MatchStrategies, ",") != "exact,author_subject" || cfg.MatchMinScore != DefaultMatchMinScore {
+		t.Errorf("Expected match defaults, got %v/%d", cfg.MatchStrategies, cfg.MatchMinScore)
+	}
+	if cfg.Notify.TimeoutSeconds != DefaultNotifyTimeoutSeconds || cfg.Notify.Retries != DefaultNotifyRetries {
+		t.Errorf("Expected notify defaults, got %+v", cfg.Notify)
+	}
 }
 
 func TestLoadConfig_FromFile(t *testing.T) {
@@ -101,6 +122,18 @@ func TestLoadConfig_FromEnv(t *testing.T) {
 	}
 }
 
+func TestLoadConfig_MatchStrategiesFromEnv(t *testing.T) {
+	t.Setenv("CHR_MATCH_STRATEGIES", "exact, trailer")
+
+	cfg, err := LoadConfig(filepath.Join(t.TempDir(), "non-existent.toml"))
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if got := strings.Join(cfg.MatchStrategies, ","); got != "exact,trailer" {
+		t.Errorf("Expected strategies exact,trailer, got %q", got)
+	}
+}
+
 func TestSaveConfig(t *testing.T) {
 	tmpDir := t.TempDir()
 	configFile := filepath.Join(tmpDir, "chr.toml")
@@ -166,6 +199,20 @@ func TestConfig_Set(t *testing.T) {
 		{"suffix_hml", "-new-stage", "-new-stage", func() interface{} { return cfg.SuffixHml }},
 		{"color", "false", false, func() interface{} { return cfg.Color }},
 		{"color", "true", true, func() interface{} { return cfg.Color }},
+		{"push_after_pick", "true", true, func() interface{} { return cfg.PushAfterPick }},
+		{"remote", "upstream", "upstream", func() interface{} { return cfg.Remote }},
+		{"ticket_url", "https://t/{ticket}", "https://t/{ticket}", func() interface{} { return cfg.TicketURL }},
+		{"forge", "gitlab", "gitlab", func() interface{} { return cfg.Forge }},
+		{"forge_url", "http://localhost:8080", "http://localhost:8080", func() interface{} { return cfg.ForgeURL }},
+		{"forge_repo", "acme/widgets", "acme/widgets", func() interface{} { return cfg.ForgeRepo }},
+		{"jira_url", "http://localhost:8080", "http://localhost:8080", func() interface{} { return cfg.JiraURL }},
+		{"jira_comment", "true", true, func() interface{} { return cfg.JiraComment }},
+		{"jira_transition", "In Homologation", "In Homologation", func() interface{} { return cfg.JiraTransition }},
+		{"merge_policy", "expand", "expand", func() interface{} { return cfg.MergePolicy }},
+		{"merge_mainline", "2", 2, func() interface{} { return cfg.MergeMainline }},
+		{"empty_policy", "stop", "stop", func() interface{} { return cfg.EmptyPolicy }},
+		{"match_strategies", "trailer, exact", "trailer,exact", func() interface{} { return strings.Join(cfg.MatchStrategies, ",") }},
+		{"match_min_score", "60", 60, func() interface{} { return cfg.MatchMinScore }},
 	}
 
 	for _, tt := range tests {
@@ -196,3 +243,172 @@ func TestConfig_Set_InvalidColorValue(t *testing.T) {
 		t.Error("Expected error for invalid color value, got nil")
 	}
 }
+
+func TestConfig_TicketRegexp(t *testing.T) {
+	cfg := &Config{Prefix: "ZUP-", TicketPattern: DefaultTicketPattern}
+	re, err := cfg.TicketRegexp()
+	if err != nil {
+		t.Fatalf("TicketRegexp failed: %v", err)
+	}
+	if got := re.FindAllString("feat: ZUP-12 and ZUPX-3, see ZUP-456", -1); strings.Join(got, ",") != "ZUP-12,ZUP-456" {
+		t.Errorf("Unexpected tickets: %v", got)
+	}
+
+	cfg = &Config{Prefix: "A.B-", TicketPattern: ""}
+	re, err = cfg.TicketRegexp()
+	if err != nil {
+		t.Fatalf("TicketRegexp failed: %v", err)
+	}
+	if re.MatchString("AXB-1") {
+		t.Error("Expected prefix to be matched literally")
+	}
+
+	cfg = &Config{Prefix: "ZUP-", TicketPattern: `(?:JIRA|{prefix})\d+`}
+	re, err = cfg.TicketRegexp()
+	if err != nil {
+		t.Fatalf("TicketRegexp failed: %v", err)
+	}
+	if !re.MatchString("JIRA12") || !re.MatchString("ZUP-9") {
+		t.Error("Expected custom pattern to match both ticket styles")
+	}
+
+	cfg = &Config{Prefix: "ZUP-", TicketPattern: "{prefix}45"}
+	re, err = cfg.TicketRegexp()
+	if err != nil {
+		t.Fatalf("TicketRegexp failed: %v", err)
+	}
+	if got := re.FindAllString("fix: ZUP-456, XZUP-45 and ZUP-45", -1); strings.Join(got, ",") != "ZUP-45" {
+		t.Errorf("Expected tickets to match as whole words only, got %v", got)
+	}
+
+	cfg = &Config{Prefix: "ZUP-", TicketPattern: "("}
+	if _, err := cfg.TicketRegexp(); err == nil {
+		t.Error("Expected invalid pattern to fail")
+	}
+}
+
+func TestSaveConfig_TicketPatternRoundTrip(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "chr.toml")
+	cfg := &Config{Prefix: "ZUP-", SuffixPrd: "-prd", SuffixHml: "-hml", Remote: "origin", TicketPattern: `{prefix}\d+`}
+
+	if err := SaveConfig(configFile, cfg); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+	loaded, err := LoadConfig(configFile)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if loaded.TicketPattern != cfg.TicketPattern {
+		t.Errorf("Expected ticket_pattern %q, got %q", cfg.TicketPattern, loaded.TicketPattern)
+	}
+}
+
+func TestConfig_String_MasksJiraToken(t *testing.T) {
+	cfg := &Config{JiraToken: "super-secret"}
+
+	out := cfg.String()
+	if strings.Contains(out, "super-secret") {
+		t.Errorf("Expected jira_token to be masked, got:\n%s", out)
+	}
+	if !strings.Contains(out, "jira_token: (set)") {
+		t.Errorf("Expected jira_token to be reported as set, got:\n%s", out)
+	}
+}
+
+func TestSaveConfig_NotifyRoundTrip(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "chr.toml")
+	cfg := &Config{Prefix: "ZUP-", SuffixPrd: "-prd", SuffixHml: "-hml", Remote: "origin"}
+	if err := cfg.Set("notify.webhooks", "https://hooks.example.com/a, https://hooks.example.com/b"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := cfg.Set("notify.retries", "4"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	if err := SaveConfig(configFile, cfg); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+	loaded, err := LoadConfig(configFile)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if len(loaded.Notify.Webhooks) != 2 || loaded.Notify.Webhooks[1] != "https://hooks.example.com/b" {
+		t.Errorf("Expected both webhooks to round-trip, got %v", loaded.Notify.Webhooks)
+	}
+	if loaded.Notify.Retries != 4 {
+		t.Errorf("Expected retries 4, got %d", loaded.Notify.Retries)
+	}
+	if loaded.Prefix != "ZUP-" {
+		t.Errorf("Expected top-level keys to stay outside [notify], got prefix %q", loaded.Prefix)
+	}
+}
+
+func TestSaveConfig_MatchRoundTrip(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "chr.toml")
+	cfg := &Config{Prefix: "ZUP-", MatchStrategies: []string{"patch_id", "exact"}, MatchMinScore: 70}
+
+	if err := SaveConfig(configFile, cfg); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+	loaded, err := LoadConfig(configFile)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if got := strings.Join(loaded.MatchStrategies, ","); got != "patch_id,exact" {
+		t.Errorf("Expected strategies patch_id,exact, got %q", got)
+	}
+	if loaded.MatchMinScore != 70 {
+		t.Errorf("Expected match_min_score 70, got %d", loaded.MatchMinScore)
+	}
+}
+
+func TestLoadFileConfig_IgnoresEnv(t *testing.T) {
+	t.Setenv("CHR_JIRA_TOKEN", "env-secret")
+	t.Setenv("CHR_PREFIX", "ENV-")
+
+	configFile := filepath.Join(t.TempDir(), "chr.toml")
+	if err := os.WriteFile(configFile, []byte("prefix = \"FILE-\"\n"), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := LoadFileConfig(configFile)
+	if err != nil {
+		t.Fatalf("LoadFileConfig failed: %v", err)
+	}
+	if cfg.Prefix != "FILE-" {
+		t.Errorf("Expected prefix from the file, got %q", cfg.Prefix)
+	}
+	if err := cfg.Set("remote", "upstream"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := SaveConfig(configFile, cfg); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+
+	content, err := os.ReadFile(configFile)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if strings.Contains(string(content), "env-secret") || strings.Contains(string(content), "ENV-") {
+		t.Errorf("Environment values were written to the config file:\n%s", content)
+	}
+}
+
+func TestSaveConfig_Permissions(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "chr.toml")
+	if err := os.WriteFile(configFile, nil, 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	if err := SaveConfig(configFile, &Config{JiraToken: "secret"}); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+
+	info, err := os.Stat(configFile)
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("Expected mode 0600, got %o", perm)
+	}
+}
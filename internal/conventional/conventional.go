@@ -0,0 +1,96 @@
+package conventional
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Message is a commit message parsed according to the Conventional Commits spec
+type Message struct {
+	Type       string // e.g. "feat"; empty when the header is not conventional
+	Scope      string // e.g. "api" in "feat(api): ..."
+	Breaking   bool   // "!" in the header or a BREAKING CHANGE footer
+	Subject    string // Description after the colon, or the whole header
+	Body       string
+	Footers    []Footer
+	IsStandard bool // Whether the header follows type(scope)!: subject
+}
+
+// Footer is a "Token: value" or "Token #value" trailer
+type Footer struct {
+	Token string
+	Value string
+}
+
+var (
+	// Types are lowercase words so headers like "Merge: x" or "WIP http://..." don't qualify
+	headerPattern = regexp.MustCompile(`^([a-z][a-z0-9-]*)(?:\(([^()\r\n]*)\))?(!)?: +(\S.*)$`)
+	footerPattern = regexp.MustCompile(`^(BREAKING CHANGE|[A-Za-z][A-Za-z0-9-]*)(?:: | #)(.*)$`)
+)
+
+// Parse parses a full commit message (header, optional body and footers)
+func Parse(message string) Message {
+	message = strings.ReplaceAll(message, "\r\n", "\n")
+	lines := strings.Split(strings.TrimSpace(message), "\n")
+	header := strings.TrimSpace(lines[0])
+
+	msg := Message{Subject: header}
+	if m := headerPattern.FindStringSubmatch(header); m != nil {
+		msg.Type = m[1]
+		msg.Scope = strings.TrimSpace(m[2])
+		msg.Breaking = m[3] == "!"
+		msg.Subject = strings.TrimSpace(m[4])
+		msg.IsStandard = true
+	}
+
+	if len(lines) == 1 {
+		return msg
+	}
+
+	rest := strings.Trim(strings.Join(lines[1:], "\n"), "\n")
+	paragraphs := strings.Split(rest, "\n\n")
+
+	// Footers live in the last paragraph, which must start with a footer line
+	last := paragraphs[len(paragraphs)-1]
+	if footers, ok := parseFooters(last); ok {
+		msg.Footers = footers
+		paragraphs = paragraphs[:len(paragraphs)-1]
+	}
+	msg.Body = strings.TrimSpace(strings.Join(paragraphs, "\n\n"))
+
+	for _, footer := range msg.Footers {
+		if footer.Token == "BREAKING CHANGE" || footer.Token == "BREAKING-CHANGE" {
+			msg.Breaking = true
+		}
+	}
+
+	return msg
+}
+
+// parseFooters parses a paragraph of footers. Lines that don't start a new footer
+// continue the previous footer's value.
+func parseFooters(paragraph string) ([]Footer, bool) {
+	var footers []Footer
+	for _, line := range strings.Split(paragraph, "\n") {
+		if m := footerPattern.FindStringSubmatch(line); m != nil {
+			footers = append(footers, Footer{Token: m[1], Value: strings.TrimSpace(m[2])})
+			continue
+		}
+		if len(footers) == 0 {
+			return nil, false
+		}
+		footers[len(footers)-1].Value += "\n" + line
+	}
+	return footers, len(footers) > 0
+}
+
+// FooterValues returns the values of every footer with the given token (case-insensitive)
+func (m Message) FooterValues(token string) []string {
+	var values []string
+	for _, footer := range m.Footers {
+		if strings.EqualFold(footer.Token, token) {
+			values = append(values, footer.Value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,91 @@
+package conventional
+
+import "testing"
+
+func TestParse_Header(t *testing.T) {
+	tests := []struct {
+		name       string
+		message    string
+		typ        string
+		scope      string
+		breaking   bool
+		subject    string
+		isStandard bool
+	}{
+		{"simple type", "feat: add login", "feat", "", false, "add login", true},
+		{"with scope", "fix(api): handle nil", "fix", "api", false, "handle nil", true},
+		{"breaking bang", "refactor(core)!: drop v1", "refactor", "core", true, "drop v1", true},
+		{"breaking without scope", "feat!: new format", "feat", "", true, "new format", true},
+		{"capitalized merge", "Merge: x", "", "", false, "Merge: x", false},
+		{"url in subject", "WIP http://example.com", "", "", false, "WIP http://example.com", false},
+		{"merge branch", "Merge branch 'main' into ZUP-1-hml", "", "", false, "Merge branch 'main' into ZUP-1-hml", false},
+		{"no space after colon", "feat:add login", "", "", false, "feat:add login", false},
+		{"plain subject", "update readme", "", "", false, "update readme", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := Parse(tt.message)
+			if msg.Type != tt.typ {
+				t.Errorf("Expected type %q, got %q", tt.typ, msg.Type)
+			}
+			if msg.Scope != tt.scope {
+				t.Errorf("Expected scope %q, got %q", tt.scope, msg.Scope)
+			}
+			if msg.Breaking != tt.breaking {
+				t.Errorf("Expected breaking %v, got %v", tt.breaking, msg.Breaking)
+			}
+			if msg.Subject != tt.subject {
+				t.Errorf("Expected subject %q, got %q", tt.subject, msg.Subject)
+			}
+			if msg.IsStandard != tt.isStandard {
+				t.Errorf("Expected IsStandard %v, got %v", tt.isStandard, msg.IsStandard)
+			}
+		})
+	}
+}
+
+func TestParse_BodyAndFooters(t *testing.T) {
+	message := `feat(billing): add invoices
+
+Invoices are generated monthly.
+
+Second paragraph.
+
+Refs: ZUP-456
+Reviewed-by: Alice
+BREAKING CHANGE: invoice IDs are now UUIDs
+  and old IDs are rejected
+Closes #12`
+
+	msg := Parse(message)
+
+	if msg.Body != "Invoices are generated monthly.\n\nSecond paragraph." {
+		t.Errorf("Unexpected body: %q", msg.Body)
+	}
+	if !msg.Breaking {
+		t.Error("Expected BREAKING CHANGE footer to mark the commit as breaking")
+	}
+	if len(msg.Footers) != 4 {
+		t.Fatalf("Expected 4 footers, got %d: %+v", len(msg.Footers), msg.Footers)
+	}
+	if refs := msg.FooterValues("refs"); len(refs) != 1 || refs[0] != "ZUP-456" {
+		t.Errorf("Expected Refs footer ZUP-456, got %v", refs)
+	}
+	if breaking := msg.FooterValues("BREAKING CHANGE"); len(breaking) != 1 || breaking[0] != "invoice IDs are now UUIDs\n  and old IDs are rejected" {
+		t.Errorf("Expected multi-line breaking footer, got %q", breaking)
+	}
+	if closes := msg.FooterValues("Closes"); len(closes) != 1 || closes[0] != "12" {
+		t.Errorf("Expected Closes footer 12, got %v", closes)
+	}
+}
+
+func TestParse_BodyWithoutFooters(t *testing.T) {
+	msg := Parse("fix: typo\n\nJust a body line.\nAnother one.")
+	if len(msg.Footers) != 0 {
+		t.Errorf("Expected no footers, got %+v", msg.Footers)
+	}
+	if msg.Body != "Just a body line.\nAnother one." {
+		t.Errorf("Unexpected body: %q", msg.Body)
+	}
+}
@@ -0,0 +1,167 @@
+package forge
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/carlosarraes/chr/internal/httpjson"
+)
+
+const (
+	KindGitHub = "github"
+	KindGitLab = "gitlab"
+
+	DefaultGitHubURL = "https://api.github.com"
+	DefaultGitLabURL = "https://gitlab.com/api/v4"
+
+	requestTimeout = 30 * time.Second
+)
+
+// PullRequest describes a pull/merge request to open
+type PullRequest struct {
+	Title string
+	Body  string
+	Head  string
+	Base  string
+}
+
+// Created is the pull/merge request returned by the forge
+type Created struct {
+	Number int
+	URL    string
+}
+
+// Client opens pull/merge requests on a forge
+type Client interface {
+	CreatePullRequest(pr PullRequest) (*Created, error)
+}
+
+// NewClient builds a client for kind ("github" or "gitlab"). An empty baseURL
+// uses the public API of the forge; repo is "owner/name" or a GitLab project path.
+func NewClient(kind, baseURL, repo, token string) (Client, error) {
+	if repo == "" {
+		return nil, fmt.Errorf("repository is not set")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("no %s token found in the environment (set %s)", kind, strings.Join(TokenEnvVars(kind), " or "))
+	}
+
+	httpClient := &http.Client{Timeout: requestTimeout}
+
+	switch kind {
+	case KindGitHub:
+		if baseURL == "" {
+			baseURL = DefaultGitHubURL
+		}
+		return &GitHub{BaseURL: strings.TrimSuffix(baseURL, "/"), Repo: repo, Token: token, HTTP: httpClient}, nil
+	case KindGitLab:
+		if baseURL == "" {
+			baseURL = DefaultGitLabURL
+		}
+		return &GitLab{BaseURL: strings.TrimSuffix(baseURL, "/"), Project: repo, Token: token, HTTP: httpClient}, nil
+	default:
+		return nil, fmt.Errorf("unknown forge %q (expected %s or %s)", kind, KindGitHub, KindGitLab)
+	}
+}
+
+// TokenEnvVars lists the environment variables checked for a forge token, in order
+func TokenEnvVars(kind string) []string {
+	if kind == KindGitLab {
+		return []string{"CHR_FORGE_TOKEN", "GITLAB_TOKEN"}
+	}
+	return []string{"CHR_FORGE_TOKEN", "GITHUB_TOKEN", "GH_TOKEN"}
+}
+
+// TokenFromEnv returns the first forge token set in the environment
+func TokenFromEnv(kind string) string {
+	for _, name := range TokenEnvVars(kind) {
+		if token := os.Getenv(name); token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+// RepoFromRemoteURL extracts "owner/name" (or a nested GitLab project path)
+// from an SSH or HTTPS remote URL
+func RepoFromRemoteURL(remoteURL string) (string, error) {
+	path := remoteURL
+	if u, err := url.Parse(remoteURL); err == nil && u.Scheme != "" && u.Host != "" {
+		path = u.Path
+	} else if i := strings.Index(remoteURL, ":"); i >= 0 && !strings.Contains(remoteURL[:i], "/") {
+		// scp-like syntax: git@host:owner/name.git
+		path = remoteURL[i+1:]
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if !strings.Contains(path, "/") {
+		return "", fmt.Errorf("cannot determine repository from remote URL %q", remoteURL)
+	}
+	return path, nil
+}
+
+// GitHub opens pull requests through the GitHub REST API
+type GitHub struct {
+	BaseURL string
+	Repo    string
+	Token   string
+	HTTP    *http.Client
+}
+
+// CreatePullRequest opens a pull request from pr.Head into pr.Base
+func (g *GitHub) CreatePullRequest(pr PullRequest) (*Created, error) {
+	payload := map[string]string{
+		"title": pr.Title,
+		"body":  pr.Body,
+		"head":  pr.Head,
+		"base":  pr.Base,
+	}
+	headers := map[string]string{
+		"Authorization": "Bearer " + g.Token,
+		"Accept":        "application/vnd.github+json",
+	}
+
+	var response struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := httpjson.Do(g.HTTP, http.MethodPost, g.BaseURL+"/repos/"+g.Repo+"/pulls", headers, payload, &response); err != nil {
+		return nil, fmt.Errorf("failed to create pull request: %w", err)
+	}
+	return &Created{Number: response.Number, URL: response.HTMLURL}, nil
+}
+
+// GitLab opens merge requests through the GitLab REST API
+type GitLab struct {
+	BaseURL string
+	Project string
+	Token   string
+	HTTP    *http.Client
+}
+
+// CreatePullRequest opens a merge request from pr.Head into pr.Base
+func (g *GitLab) CreatePullRequest(pr PullRequest) (*Created, error) {
+	payload := map[string]string{
+		"title":         pr.Title,
+		"description":   pr.Body,
+		"source_branch": pr.Head,
+		"target_branch": pr.Base,
+	}
+	headers := map[string]string{
+		"PRIVATE-TOKEN": g.Token,
+	}
+
+	var response struct {
+		IID    int    `json:"iid"`
+		WebURL string `json:"web_url"`
+	}
+	endpoint := g.BaseURL + "/projects/" + url.PathEscape(g.Project) + "/merge_requests"
+	if err := httpjson.Do(g.HTTP, http.MethodPost, endpoint, headers, payload, &response); err != nil {
+		return nil, fmt.Errorf("failed to create merge request: %w", err)
+	}
+	return &Created{Number: response.IID, URL: response.WebURL}, nil
+}
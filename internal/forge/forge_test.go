@@ -0,0 +1,136 @@
+package forge
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRepoFromRemoteURL(t *testing.T) {
+	tests := []struct {
+		remoteURL string
+		expected  string
+		wantErr   bool
+	}{
+		{"git@github.com:acme/widgets.git", "acme/widgets", false},
+		{"https://github.com/acme/widgets.git", "acme/widgets", false},
+		{"https://github.com/acme/widgets", "acme/widgets", false},
+		{"ssh://git@gitlab.example.com:2222/group/sub/project.git", "group/sub/project", false},
+		{"/tmp/bare-repo", "tmp/bare-repo", false},
+		{"widgets", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.remoteURL, func(t *testing.T) {
+			repo, err := RepoFromRemoteURL(tt.remoteURL)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got %q", repo)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if repo != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, repo)
+			}
+		})
+	}
+}
+
+func TestGitHub_CreatePullRequest(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repos/acme/widgets/pulls" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer secret" {
+			t.Errorf("Unexpected Authorization header %q", auth)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"number": 42, "html_url": "https://github.example/acme/widgets/pull/42"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(KindGitHub, server.URL, "acme/widgets", "secret")
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	created, err := client.CreatePullRequest(PullRequest{Title: "Pick", Body: "body", Head: "ZUP-1-hml-pick-1", Base: "ZUP-1-hml"})
+	if err != nil {
+		t.Fatalf("CreatePullRequest failed: %v", err)
+	}
+	if created.Number != 42 || created.URL != "https://github.example/acme/widgets/pull/42" {
+		t.Errorf("Unexpected result: %+v", created)
+	}
+	if got["head"] != "ZUP-1-hml-pick-1" || got["base"] != "ZUP-1-hml" || got["body"] != "body" {
+		t.Errorf("Unexpected payload: %+v", got)
+	}
+}
+
+func TestGitLab_CreatePullRequest(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/projects/group%2Fproject/merge_requests" {
+			t.Errorf("Unexpected path %s", r.URL.EscapedPath())
+		}
+		if token := r.Header.Get("PRIVATE-TOKEN"); token != "secret" {
+			t.Errorf("Unexpected PRIVATE-TOKEN header %q", token)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"iid": 7, "web_url": "https://gitlab.example/group/project/-/merge_requests/7"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(KindGitLab, server.URL, "group/project", "secret")
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	created, err := client.CreatePullRequest(PullRequest{Title: "Pick", Body: "body", Head: "pick", Base: "target"})
+	if err != nil {
+		t.Fatalf("CreatePullRequest failed: %v", err)
+	}
+	if created.Number != 7 {
+		t.Errorf("Expected iid 7, got %d", created.Number)
+	}
+	if got["source_branch"] != "pick" || got["target_branch"] != "target" || got["description"] != "body" {
+		t.Errorf("Unexpected payload: %+v", got)
+	}
+}
+
+func TestCreatePullRequest_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"message": "A pull request already exists"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(KindGitHub, server.URL, "acme/widgets", "secret")
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	_, err = client.CreatePullRequest(PullRequest{Title: "Pick", Head: "a", Base: "b"})
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Expected error with response message, got %v", err)
+	}
+}
+
+func TestNewClient_Errors(t *testing.T) {
+	if _, err := NewClient("bitbucket", "", "acme/widgets", "secret"); err == nil {
+		t.Error("Expected error for unknown forge")
+	}
+	if _, err := NewClient(KindGitHub, "", "acme/widgets", ""); err == nil {
+		t.Error("Expected error for missing token")
+	}
+	if _, err := NewClient(KindGitHub, "", "", "secret"); err == nil {
+		t.Error("Expected error for missing repository")
+	}
+}
@@ -0,0 +1,140 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Backends answering read-only queries
+const (
+	BackendGit   = "git"    // Run the git CLI (default)
+	BackendGoGit = "go-git" // Read the repository in-process with go-git
+)
+
+// Backend answers the read-only queries chr makes most often: ref resolution,
+// log walks and user lookup. Commands that change the repository, such as
+// cherry-picks, fetches and pushes, always run the git CLI.
+type Backend interface {
+	CurrentBranch(ctx context.Context, repoDir string) (string, error)
+	CurrentUser(ctx context.Context, repoDir string) (string, error)
+	ResolveCommit(ctx context.Context, repoDir, ref string) (string, error)
+	AheadBehind(ctx context.Context, repoDir, localRef, remoteRef string) (int, int, error)
+	Log(ctx context.Context, repoDir, targetRef, sourceRef string, limit int) ([]Commit, error)
+	FirstParentHashes(ctx context.Context, repoDir, targetRef, sourceRef string) ([]string, error)
+	MergedCommits(ctx context.Context, repoDir, mergeHash string) ([]string, error)
+}
+
+// NewBackend returns the backend with the given name
+func NewBackend(name string) (Backend, error) {
+	switch name {
+	case "", BackendGit:
+		return CLIBackend{}, nil
+	case BackendGoGit:
+		return NewGoGitBackend(), nil
+	default:
+		return nil, fmt.Errorf("unknown backend '%s' (valid: %s, %s)", name, BackendGit, BackendGoGit)
+	}
+}
+
+type backendKey struct{}
+
+// WithBackend returns a context whose read-only queries are answered by b
+func WithBackend(ctx context.Context, b Backend) context.Context {
+	return context.WithValue(ctx, backendKey{}, b)
+}
+
+// BackendFrom returns the backend carried by ctx, or the git CLI one
+func BackendFrom(ctx context.Context) Backend {
+	if b, ok := ctx.Value(backendKey{}).(Backend); ok {
+		return b
+	}
+	return CLIBackend{}
+}
+
+// CLIBackend answers queries by running git through the package Runner
+type CLIBackend struct{}
+
+// CurrentBranch implements Backend
+func (CLIBackend) CurrentBranch(ctx context.Context, repoDir string) (string, error) {
+	output, err := run(ctx, repoDir, "branch", "--show-current")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// CurrentUser implements Backend
+func (CLIBackend) CurrentUser(ctx context.Context, repoDir string) (string, error) {
+	output, err := run(ctx, repoDir, "config", "user.name")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ResolveCommit implements Backend
+func (CLIBackend) ResolveCommit(ctx context.Context, repoDir, ref string) (string, error) {
+	output, err := run(ctx, repoDir, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// AheadBehind implements Backend
+func (CLIBackend) AheadBehind(ctx context.Context, repoDir, localRef, remoteRef string) (int, int, error) {
+	output, err := run(ctx, repoDir, "rev-list", "--left-right", "--count", fmt.Sprintf("%s...%s", localRef, remoteRef))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	fields := strings.Fields(string(output))
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output: %q", string(output))
+	}
+
+	ahead, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("unexpected rev-list output: %q", string(output))
+	}
+	behind, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("unexpected rev-list output: %q", string(output))
+	}
+
+	return ahead, behind, nil
+}
+
+// Log implements Backend
+func (CLIBackend) Log(ctx context.Context, repoDir, targetRef, sourceRef string, limit int) ([]Commit, error) {
+	args := []string{"log", "^" + targetRef, sourceRef, logFormat, "--date=short"}
+	if limit > 0 {
+		args = append(args, fmt.Sprintf("-%d", limit))
+	}
+
+	output, err := run(ctx, repoDir, args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseLogOutput(string(output)), nil
+}
+
+// FirstParentHashes implements Backend
+func (CLIBackend) FirstParentHashes(ctx context.Context, repoDir, targetRef, sourceRef string) ([]string, error) {
+	output, err := run(ctx, repoDir, "log", "--first-parent", "--format=%h", "^"+targetRef, sourceRef)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// MergedCommits implements Backend
+func (CLIBackend) MergedCommits(ctx context.Context, repoDir, mergeHash string) ([]string, error) {
+	output, err := run(ctx, repoDir, "log", "--no-merges", "--format=%h", mergeHash+"^1.."+mergeHash)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(output)), nil
+}
@@ -0,0 +1,71 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotRepository is returned when chr runs outside a git working tree
+var ErrNotRepository = errors.New("not a git repository")
+
+// BranchNotFoundError reports a branch that exists neither locally nor on the remote
+type BranchNotFoundError struct {
+	Branch string
+}
+
+func (e *BranchNotFoundError) Error() string {
+	return fmt.Sprintf("branch '%s' does not exist", e.Branch)
+}
+
+// DirtyTreeError reports uncommitted changes that would get in the way of a cherry-pick
+type DirtyTreeError struct {
+	Files []string
+}
+
+func (e *DirtyTreeError) Error() string {
+	return fmt.Sprintf("working tree has uncommitted changes (%s); commit or stash them first", strings.Join(e.Files, ", "))
+}
+
+// ConflictError reports a cherry-pick stopped on a conflict, left in progress
+type ConflictError struct {
+	Commit string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("cherry-pick of %s stopped on conflicts; resolve them and run chr pick --continue", e.Commit)
+}
+
+// AlreadyAppliedError reports a cherry-pick stopped on a commit whose changes are
+// already on the target (empty policy "stop"), left in progress
+type AlreadyAppliedError struct {
+	Commit string
+}
+
+func (e *AlreadyAppliedError) Error() string {
+	return fmt.Sprintf("cherry-pick stopped: %s is already applied on the target; run chr pick --continue to skip it", e.Commit)
+}
+
+// InterruptedError reports a pick cancelled between two commits, e.g. by Ctrl-C.
+// Nothing is left in progress; the commits not picked yet are pending.
+type InterruptedError struct {
+	Pending int
+}
+
+func (e *InterruptedError) Error() string {
+	return fmt.Sprintf("pick interrupted with %d commits left; run chr pick --continue to pick them or chr pick --abort to undo the pick", e.Pending)
+}
+
+func (e *InterruptedError) Unwrap() error {
+	return context.Canceled
+}
+
+// IsPickStopped reports whether err is a cherry-pick that stopped midway and
+// can be resumed with chr pick --continue
+func IsPickStopped(err error) bool {
+	var conflict *ConflictError
+	var applied *AlreadyAppliedError
+	var interrupted *InterruptedError
+	return errors.As(err, &conflict) || errors.As(err, &applied) || errors.As(err, &interrupted)
+}
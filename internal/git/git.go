@@ -1,19 +1,45 @@
 package git
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"os/exec"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
 
 type Commit struct {
-	Hash    string
-	Author  string
-	Message string
-	Date    string
+	Hash    string   `json:"hash"`
+	Author  string   `json:"author"`
+	Message string   `json:"message"`
+	Date    string   `json:"date"`
+	Body    string   `json:"body,omitempty"`
+	Parents []string `json:"parents,omitempty"`
+	Files   []string `json:"files,omitempty"`    // Changed files, only set by LoadChangedFiles
+	PatchID string   `json:"patch_id,omitempty"` // Stable patch ID of the diff, only set by LoadPatchIDs
+	Via     string   `json:"via,omitempty"`      // Merge that brought the commit into the selection
+	// PickedFrom are the source commits chr picked this one from (several once
+	// squashed), only set by provenance.Load
+	PickedFrom []string `json:"picked_from,omitempty"`
 }
 
+// IsMerge reports whether the commit has more than one parent
+func (c Commit) IsMerge() bool {
+	return len(c.Parents) > 1
+}
+
+// logFormat separates records with RS and fields with US so subjects and bodies
+// may contain any printable character
+const logFormat = "--format=%x1e%h%x1f%an%x1f%s%x1f%ad%x1f%p%x1f%b"
+
 type DateFilterType int
 
 const (
@@ -48,163 +74,354 @@ func NewYesterdayFilter() *DateFilter {
 	}
 }
 
+// CheckRepository returns ErrNotRepository when repoDir is not inside a git working tree
+func CheckRepository(ctx context.Context, repoDir string) error {
+	output, err := run(ctx, repoDir, "rev-parse", "--is-inside-work-tree")
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+	if err != nil || strings.TrimSpace(string(output)) != "true" {
+		return fmt.Errorf("%s: %w", repoDir, ErrNotRepository)
+	}
+	return nil
+}
+
+// CheckCleanTree returns a DirtyTreeError when tracked files have uncommitted changes
+func CheckCleanTree(ctx context.Context, repoDir string) error {
+	output, err := run(ctx, repoDir, "status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return fmt.Errorf("failed to get working tree status: %w", err)
+	}
+
+	var files []string
+	for _, line := range strings.Split(strings.TrimRight(string(output), "\n"), "\n") {
+		if len(line) > 3 {
+			files = append(files, line[3:])
+		}
+	}
+	if len(files) > 0 {
+		return &DirtyTreeError{Files: files}
+	}
+	return nil
+}
+
 // GetCurrentBranch returns the current git branch name
-func GetCurrentBranch(repoDir string) (string, error) {
-	cmd := exec.Command("git", "branch", "--show-current")
-	cmd.Dir = repoDir
-	output, err := cmd.Output()
+func GetCurrentBranch(ctx context.Context, repoDir string) (string, error) {
+	branch, err := BackendFrom(ctx).CurrentBranch(ctx, repoDir)
 	if err != nil {
 		return "", fmt.Errorf("failed to get current branch: %w", err)
 	}
-	return strings.TrimSpace(string(output)), nil
+	return branch, nil
 }
 
 // GetCurrentUser returns the current git user name
-func GetCurrentUser(repoDir string) (string, error) {
-	cmd := exec.Command("git", "config", "user.name")
-	cmd.Dir = repoDir
-	output, err := cmd.Output()
+func GetCurrentUser(ctx context.Context, repoDir string) (string, error) {
+	name, err := BackendFrom(ctx).CurrentUser(ctx, repoDir)
 	if err != nil {
 		return "", fmt.Errorf("failed to get git user name: %w", err)
 	}
-	return strings.TrimSpace(string(output)), nil
+	return name, nil
 }
 
 // BranchExists checks if a git branch exists
-func BranchExists(repoDir, branch string) (bool, error) {
-	cmd := exec.Command("git", "rev-parse", "--verify", branch)
-	cmd.Dir = repoDir
-	err := cmd.Run()
-	if err != nil {
-		// If the command fails, the branch doesn't exist
+func BranchExists(ctx context.Context, repoDir, branch string) (bool, error) {
+	if _, err := BackendFrom(ctx).ResolveCommit(ctx, repoDir, branch); err != nil {
+		// If the ref doesn't resolve, the branch doesn't exist
 		return false, nil
 	}
 	return true, nil
 }
 
-func FetchBranches(repoDir string, debug bool, branches ...string) error {
-	checkCmd := exec.Command("git", "remote", "get-url", "origin")
-	checkCmd.Dir = repoDir
-	if err := checkCmd.Run(); err != nil {
-		if debug {
-			fmt.Printf("Debug: No origin remote found, skipping fetch\n")
-		}
-		return nil
+// GetCommitHash returns the full hash of the commit a ref points to
+func GetCommitHash(ctx context.Context, repoDir, ref string) (string, error) {
+	hash, err := BackendFrom(ctx).ResolveCommit(ctx, repoDir, ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve '%s': %w", ref, err)
 	}
+	return hash, nil
+}
 
-	if debug {
-		fmt.Printf("Debug: Fetching from origin...\n")
+// GetCommitHashes returns the full hashes of the commits refs point to, in order,
+// with a single git call
+func GetCommitHashes(ctx context.Context, repoDir string, refs []string) ([]string, error) {
+	if len(refs) == 0 {
+		return nil, nil
 	}
-	cmd := exec.Command("git", "fetch", "origin")
-	cmd.Dir = repoDir
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to fetch from origin: %w", err)
+	args := []string{"rev-parse"}
+	for _, ref := range refs {
+		args = append(args, ref+"^{commit}")
 	}
-	if debug {
-		fmt.Printf("Debug: Fetch completed successfully\n")
+	output, err := run(ctx, repoDir, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve commits: %w", err)
 	}
-	return nil
+	hashes := strings.Fields(string(output))
+	if len(hashes) != len(refs) {
+		return nil, fmt.Errorf("unexpected rev-parse output: %q", string(output))
+	}
+	return hashes, nil
 }
 
-// GetCommits gets commits that are in sourceBranch but not in targetBranch
-func GetCommits(repoDir, targetBranch, sourceBranch string, limit int, debug bool) ([]Commit, error) {
-	var sourceRef, targetRef string
-	
-	if localExists, _ := BranchExists(repoDir, sourceBranch); localExists {
-		sourceRef = sourceBranch
-		if debug {
-			fmt.Printf("Debug: Using local source branch: %s\n", sourceRef)
-		}
-	} else {
-		if err := FetchBranches(repoDir, debug, targetBranch, sourceBranch); err != nil {
-			return nil, fmt.Errorf("failed to fetch branches: %w", err)
-		}
-		
-		sourceRemoteRef := fmt.Sprintf("origin/%s", sourceBranch)
-		if remoteExists, _ := BranchExists(repoDir, sourceRemoteRef); remoteExists {
-			sourceRef = sourceRemoteRef
-			if debug {
-				fmt.Printf("Debug: Local source branch not found, using remote: %s\n", sourceRef)
-			}
-		} else {
-			sourceRef = sourceBranch
-			if debug {
-				fmt.Printf("Debug: Neither local nor remote source branch found, using: %s\n", sourceRef)
+// GetAheadBehind returns how many commits localRef has that remoteRef lacks (ahead)
+// and how many commits remoteRef has that localRef lacks (behind)
+func GetAheadBehind(ctx context.Context, repoDir, localRef, remoteRef string) (int, int, error) {
+	ahead, behind, err := BackendFrom(ctx).AheadBehind(ctx, repoDir, localRef, remoteRef)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to compare '%s' with '%s': %w", localRef, remoteRef, err)
+	}
+	return ahead, behind, nil
+}
+
+// PushBranch pushes a local branch to the remote and returns the pushed commit hash.
+// When the push is rejected, the error reports how far the local branch is ahead of
+// and behind its remote counterpart.
+func PushBranch(ctx context.Context, repoDir, remote, branch string, forceWithLease bool) (string, error) {
+	sha, err := GetCommitHash(ctx, repoDir, branch)
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{"push"}
+	if forceWithLease {
+		args = append(args, "--force-with-lease")
+	}
+	args = append(args, remote, fmt.Sprintf("refs/heads/%s:refs/heads/%s", branch, branch))
+
+	if _, err := run(ctx, repoDir, args...); err != nil {
+		if strings.Contains(err.Error(), "rejected") {
+			remoteRef := RemoteRef(remote, branch)
+
+			_, _ = run(ctx, repoDir, "fetch", remote, branch)
+
+			ahead, behind, cmpErr := GetAheadBehind(ctx, repoDir, branch, remoteRef)
+			if cmpErr != nil {
+				return "", fmt.Errorf("push of '%s' to '%s' was rejected: %w", branch, remote, err)
 			}
+			return "", fmt.Errorf("push of '%s' to '%s' was rejected: local branch is %d ahead and %d behind %s", branch, remote, ahead, behind, remoteRef)
 		}
+		return "", fmt.Errorf("failed to push '%s' to '%s': %w", branch, remote, err)
 	}
-	
-	if localExists, _ := BranchExists(repoDir, targetBranch); localExists {
-		targetRef = targetBranch
-		if debug {
-			fmt.Printf("Debug: Using local target branch: %s\n", targetRef)
+
+	return sha, nil
+}
+
+// EnsureConfigValue adds a value to a multi-valued repository config key, such
+// as notes.rewriteRef, unless the key already has it
+func EnsureConfigValue(ctx context.Context, repoDir, key, value string) error {
+	// Exit code 1 just means the key is unset
+	output, _ := run(ctx, repoDir, "config", "--get-all", key)
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.TrimSpace(line) == value {
+			return nil
 		}
-	} else {
-		if err := FetchBranches(repoDir, debug, targetBranch, sourceBranch); err != nil {
-			return nil, fmt.Errorf("failed to fetch branches: %w", err)
+	}
+	if _, err := run(ctx, repoDir, "config", "--add", key, value); err != nil {
+		return fmt.Errorf("failed to add %s to %s: %w", value, key, err)
+	}
+	return nil
+}
+
+// GetGitDir returns the absolute path of the repository's .git directory
+func GetGitDir(ctx context.Context, repoDir string) (string, error) {
+	output, err := run(ctx, repoDir, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", fmt.Errorf("not a git repository: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// Operations that can leave the repository waiting for user action
+const (
+	OperationNone       = ""
+	OperationCherryPick = "cherry-pick"
+	OperationRebase     = "rebase"
+	OperationMerge      = "merge"
+)
+
+// GetOperationInProgress returns which cherry-pick, rebase or merge is in progress, if any
+func GetOperationInProgress(ctx context.Context, repoDir string) (string, error) {
+	gitDir, err := GetGitDir(ctx, repoDir)
+	if err != nil {
+		return OperationNone, err
+	}
+
+	if CherryPickInProgress(ctx, repoDir) {
+		return OperationCherryPick, nil
+	}
+	for _, dir := range []string{"rebase-merge", "rebase-apply"} {
+		if _, err := os.Stat(filepath.Join(gitDir, dir)); err == nil {
+			return OperationRebase, nil
 		}
+	}
+	if _, err := os.Stat(filepath.Join(gitDir, "MERGE_HEAD")); err == nil {
+		return OperationMerge, nil
+	}
+	return OperationNone, nil
+}
+
+// CherryPickInProgress reports whether a cherry-pick is waiting for conflict resolution
+func CherryPickInProgress(ctx context.Context, repoDir string) bool {
+	_, err := run(ctx, repoDir, "rev-parse", "--verify", "--quiet", "CHERRY_PICK_HEAD")
+	return err == nil
+}
+
+// GetRemoteURL returns the URL configured for a remote
+func GetRemoteURL(ctx context.Context, repoDir, remote string) (string, error) {
+	output, err := run(ctx, repoDir, "remote", "get-url", remote)
+	if err != nil {
+		return "", fmt.Errorf("failed to get URL of remote '%s': %w", remote, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// CreateBranch creates a branch at startRef and checks it out
+func CreateBranch(ctx context.Context, repoDir, branch, startRef string) error {
+	if _, err := run(ctx, repoDir, "checkout", "-b", branch, startRef); err != nil {
+		return fmt.Errorf("failed to create branch '%s' from '%s': %w", branch, startRef, err)
+	}
+	return nil
+}
+
+// RemoteExists checks if a git remote is configured
+func RemoteExists(ctx context.Context, repoDir, remote string) bool {
+	_, err := run(ctx, repoDir, "remote", "get-url", remote)
+	return err == nil
+}
 		
-		targetRemoteRef := fmt.Sprintf("origin/%s", targetBranch)
-		if remoteExists, _ := BranchExists(repoDir, targetRemoteRef); remoteExists {
-			targetRef = targetRemoteRef
-			if debug {
-				fmt.Printf("Debug: Local target branch not found, using remote: %s\n", targetRef)
-			}
-		} else {
-			targetRef = targetBranch
-			if debug {
-				fmt.Printf("Debug: Neither local nor remote target branch found, using: %s\n", targetRef)
-			}
+// FetchBranches fetches only the given branches from remote into their
+// remote-tracking refs. Branches missing on the remote are skipped.
+func FetchBranches(ctx context.Context, repoDir, remote string, log *slog.Logger, branches ...string) error {
+	if !RemoteExists(ctx, repoDir, remote) {
+		log.Debug("no remote found, skipping fetch", "remote", remote)
+		return nil
+	}
+	
+	lsArgs := []string{"ls-remote", "--heads", remote}
+	for _, branch := range branches {
+		lsArgs = append(lsArgs, "refs/heads/"+branch)
+	}
+	lsOutput, err := run(ctx, repoDir, lsArgs...)
+	if err != nil {
+		return fmt.Errorf("failed to list branches on %s: %w", remote, err)
+	}
+		
+	var refspecs []string
+	for _, line := range strings.Split(strings.TrimSpace(string(lsOutput)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
 		}
+		branch := strings.TrimPrefix(fields[1], "refs/heads/")
+		refspecs = append(refspecs, fmt.Sprintf("+refs/heads/%s:refs/remotes/%s/%s", branch, remote, branch))
 	}
 
-	// Use git log to find commits in sourceBranch that are not in targetBranch
-	args := []string{"log",
-		fmt.Sprintf("^%s", targetRef),
-		sourceRef,
-		"--format=%h|%an|%s|%ad",
-		"--date=short",
+	if len(refspecs) == 0 {
+		log.Debug("no branch exists on the remote, skipping fetch", "remote", remote, "branches", branches)
+		return nil
 	}
 
-	if limit > 0 {
-		args = append(args, fmt.Sprintf("-%d", limit))
+	log.Info("fetching branches", "remote", remote, "refspecs", refspecs)
+	if _, err := run(ctx, repoDir, append([]string{"fetch", remote}, refspecs...)...); err != nil {
+		return fmt.Errorf("failed to fetch from %s: %w", remote, err)
 	}
+	log.Debug("fetch completed", "remote", remote)
+	return nil
+}
 
-	if debug {
-		fmt.Printf("Debug: Running git command: git %s\n", strings.Join(args, " "))
-		fmt.Printf("Debug: sourceRef=%s, targetRef=%s\n", sourceRef, targetRef)
+// RemoteRef returns the remote-tracking ref name of a branch
+func RemoteRef(remote, branch string) string {
+	return fmt.Sprintf("%s/%s", remote, branch)
+}
+
+// ResolveRef returns the local branch when it exists, otherwise its remote-tracking ref
+func ResolveRef(ctx context.Context, repoDir, remote, branch string, log *slog.Logger) string {
+	if localExists, _ := BranchExists(ctx, repoDir, branch); localExists {
+		log.Debug("using local branch", "branch", branch)
+		return branch
+	}
+	
+	remoteRef := RemoteRef(remote, branch)
+	if remoteExists, _ := BranchExists(ctx, repoDir, remoteRef); remoteExists {
+		log.Debug("local branch not found, using remote", "branch", branch, "ref", remoteRef)
+		return remoteRef
 	}
 
-	cmd := exec.Command("git", args...)
-	cmd.Dir = repoDir
+	log.Debug("neither local nor remote branch found", "branch", branch)
+	return branch
+}
+
+// SyncStatus describes how a local branch relates to its remote-tracking counterpart
+type SyncStatus struct {
+	Branch    string
+	RemoteRef string
+	HasLocal  bool
+	HasRemote bool
+	Ahead     int // Commits only in the local branch
+	Behind    int // Commits only in the remote-tracking ref
+}
+
+// Diverged reports whether local and remote both have commits the other lacks
+func (s SyncStatus) Diverged() bool {
+	return s.Ahead > 0 && s.Behind > 0
+}
+
+// GetSyncStatus compares a local branch with its remote-tracking ref.
+// Ahead and Behind are 0 when either side is missing.
+func GetSyncStatus(ctx context.Context, repoDir, remote, branch string) (SyncStatus, error) {
+	status := SyncStatus{Branch: branch, RemoteRef: RemoteRef(remote, branch)}
+	status.HasLocal, _ = BranchExists(ctx, repoDir, branch)
+	status.HasRemote, _ = BranchExists(ctx, repoDir, status.RemoteRef)
+	if !status.HasLocal || !status.HasRemote {
+		return status, nil
+	}
 
-	output, err := cmd.Output()
+	ahead, behind, err := GetAheadBehind(ctx, repoDir, branch, status.RemoteRef)
 	if err != nil {
-		if debug {
-			if exitError, ok := err.(*exec.ExitError); ok {
-				fmt.Printf("Debug: Git command failed with stderr: %s\n", string(exitError.Stderr))
-			}
-		}
-		return nil, fmt.Errorf("failed to get commits with command 'git %s': %w", strings.Join(args, " "), err)
+		return status, err
 	}
-	
-	if debug {
-		fmt.Printf("Debug: Git command succeeded, output length: %d bytes\n", len(output))
+	status.Ahead = ahead
+	status.Behind = behind
+	return status, nil
+}
+
+// GetCommits gets commits that are in sourceRef but not in targetRef
+func GetCommits(ctx context.Context, repoDir, targetRef, sourceRef string, limit int, log *slog.Logger) ([]Commit, error) {
+	log.Debug("listing commits", "source", sourceRef, "target", targetRef, "limit", limit)
+
+	commits, err := BackendFrom(ctx).Log(ctx, repoDir, targetRef, sourceRef, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commits in %s but not in %s: %w", sourceRef, targetRef, err)
 	}
 
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	if len(lines) == 1 && lines[0] == "" {
-		return []Commit{}, nil // No commits found
+	log.Debug("listed commits", "count", len(commits))
+	return commits, nil
+}
+
+// GetCommitsByHash returns the commits with the given hashes, in that order
+func GetCommitsByHash(ctx context.Context, repoDir string, hashes []string) ([]Commit, error) {
+	if len(hashes) == 0 {
+		return nil, nil
 	}
 
-	commits := make([]Commit, 0, len(lines))
-	for _, line := range lines {
-		if line == "" {
+	args := append([]string{"log", "--no-walk=unsorted", logFormat, "--date=short"}, hashes...)
+	output, err := run(ctx, repoDir, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commits: %w", err)
+	}
+	return parseLogOutput(string(output)), nil
+}
+
+// parseLogOutput parses git log output produced with logFormat
+func parseLogOutput(output string) []Commit {
+	records := strings.Split(output, "\x1e")
+	commits := make([]Commit, 0, len(records))
+	for _, record := range records {
+		if strings.TrimSpace(record) == "" {
 			continue
 		}
 
-		parts := strings.Split(line, "|")
-		if len(parts) < 4 {
+		parts := strings.SplitN(record, "\x1f", 6)
+		if len(parts) < 6 {
 			continue
 		}
 
@@ -213,12 +430,242 @@ func GetCommits(repoDir, targetBranch, sourceBranch string, limit int, debug boo
 			Author:  parts[1],
 			Message: parts[2],
 			Date:    parts[3],
+			Parents: strings.Fields(parts[4]),
+			Body:    strings.TrimSpace(parts[5]),
 		}
 
 		commits = append(commits, commit)
 	}
 
-	return commits, nil
+	return commits
+}
+
+// GetFirstParentHashes returns the abbreviated hashes on sourceRef's first-parent
+// chain that are not in targetRef, i.e. the commits made or merged directly on it
+func GetFirstParentHashes(ctx context.Context, repoDir, targetRef, sourceRef string) (map[string]bool, error) {
+	list, err := BackendFrom(ctx).FirstParentHashes(ctx, repoDir, targetRef, sourceRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list first-parent commits of %s: %w", sourceRef, err)
+	}
+
+	hashes := make(map[string]bool)
+	for _, hash := range list {
+		hashes[hash] = true
+	}
+	return hashes, nil
+}
+
+// GetMergedCommits returns the abbreviated hashes of the non-merge commits a merge
+// brought in, i.e. those reachable from the merge but not from its first parent
+func GetMergedCommits(ctx context.Context, repoDir, mergeHash string) ([]string, error) {
+	hashes, err := BackendFrom(ctx).MergedCommits(ctx, repoDir, mergeHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits merged by %s: %w", mergeHash, err)
+	}
+	return hashes, nil
+}
+
+// LoadChangedFiles fills in the changed-file list of each commit
+func LoadChangedFiles(ctx context.Context, repoDir string, commits []Commit) error {
+	const batchSize = 500
+
+	for start := 0; start < len(commits); start += batchSize {
+		end := start + batchSize
+		if end > len(commits) {
+			end = len(commits)
+		}
+
+		args := []string{"show", "--name-only", "--no-renames", "--format=%x1e%h"}
+		for _, commit := range commits[start:end] {
+			args = append(args, commit.Hash)
+		}
+
+		output, err := run(ctx, repoDir, args...)
+		if err != nil {
+			return fmt.Errorf("failed to list changed files: %w", err)
+		}
+
+		files := make(map[string][]string)
+		for _, record := range strings.Split(string(output), "\x1e") {
+			lines := strings.Split(strings.TrimSpace(record), "\n")
+			if len(lines) == 0 || lines[0] == "" {
+				continue
+			}
+			for _, line := range lines[1:] {
+				if line = strings.TrimSpace(line); line != "" {
+					files[lines[0]] = append(files[lines[0]], line)
+				}
+			}
+		}
+
+		for i := start; i < end; i++ {
+			commits[i].Files = files[commits[i].Hash]
+		}
+	}
+
+	return nil
+}
+
+// LoadPatchIDs fills in the patch ID of each non-merge commit: a hash of its diff
+// that stays the same when the commit is cherry-picked, rebased or reworded.
+// Commits with an empty diff and merges get no patch ID.
+func LoadPatchIDs(ctx context.Context, repoDir string, commits []Commit) error {
+	const batchSize = 500
+
+	var hashes []string
+	for _, commit := range commits {
+		if !commit.IsMerge() {
+			hashes = append(hashes, commit.Hash)
+		}
+	}
+
+	// git patch-id reports full hashes; commits carry abbreviated ones
+	ids := make(map[string]string)
+	for start := 0; start < len(hashes); start += batchSize {
+		end := start + batchSize
+		if end > len(hashes) {
+			end = len(hashes)
+		}
+
+		args := append([]string{"show", "--no-color", "--no-ext-diff", "--format=commit %H", "--patch"}, hashes[start:end]...)
+		patches, err := run(ctx, repoDir, args...)
+		if err != nil {
+			return fmt.Errorf("failed to read commit diffs: %w", err)
+		}
+		output, err := RunnerFrom(ctx).Run(ctx, Command{Dir: repoDir, Args: []string{"patch-id", "--stable"}, Stdin: patches})
+		if err != nil {
+			return fmt.Errorf("failed to compute patch IDs: %w", err)
+		}
+
+		for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+			if fields := strings.Fields(line); len(fields) == 2 {
+				ids[fields[1]] = fields[0]
+			}
+		}
+	}
+
+	full := make([]string, 0, len(ids))
+	for hash := range ids {
+		full = append(full, hash)
+	}
+	sort.Strings(full)
+	for i := range commits {
+		if n := sort.SearchStrings(full, commits[i].Hash); n < len(full) && strings.HasPrefix(full[n], commits[i].Hash) {
+			commits[i].PatchID = ids[full[n]]
+		}
+	}
+	return nil
+}
+
+// FilterCommitsByMessage keeps commits whose full message matches the pattern
+func FilterCommitsByMessage(commits []Commit, pattern *regexp.Regexp) []Commit {
+	if pattern == nil {
+		return commits
+	}
+
+	filtered := make([]Commit, 0)
+	for _, commit := range commits {
+		if pattern.MatchString(commit.FullMessage()) {
+			filtered = append(filtered, commit)
+		}
+	}
+	return filtered
+}
+
+// FilterCommitsByPath keeps commits that change at least one file matching the
+// include patterns (any file when include is empty) that is not matched by the
+// exclude patterns. Commits must have their Files loaded.
+func FilterCommitsByPath(commits []Commit, include, exclude []string) []Commit {
+	if len(include) == 0 && len(exclude) == 0 {
+		return commits
+	}
+
+	filtered := make([]Commit, 0)
+	for _, commit := range commits {
+		for _, file := range commit.Files {
+			if matchAnyPath(exclude, file) {
+				continue
+			}
+			if len(include) == 0 || matchAnyPath(include, file) {
+				filtered = append(filtered, commit)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// matchAnyPath reports whether file matches any of the patterns
+func matchAnyPath(patterns []string, file string) bool {
+	for _, pattern := range patterns {
+		if MatchPath(pattern, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchPath matches a repository path against a glob pattern. "*" and "?" stay
+// within a path segment and "**" spans segments. A pattern without glob
+// characters matches that path and everything below it; a pattern without a
+// slash also matches file names in any directory.
+func MatchPath(pattern, file string) bool {
+	pattern = strings.Trim(pattern, "/")
+	if pattern == "" {
+		return false
+	}
+
+	if !strings.ContainsAny(pattern, "*?[") {
+		return file == pattern || strings.HasPrefix(file, pattern+"/")
+	}
+
+	re, err := globToRegexp(pattern)
+	if err != nil {
+		return false
+	}
+	if re.MatchString(file) {
+		return true
+	}
+	if !strings.Contains(pattern, "/") {
+		return re.MatchString(path.Base(file))
+	}
+	return false
+}
+
+// globToRegexp converts a glob pattern into an anchored regular expression
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "/**") && i+3 == len(pattern):
+			b.WriteString("(?:/.*)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				b.WriteString(regexp.QuoteMeta(string(c)))
+				continue
+			}
+			b.WriteString(pattern[i : i+end+1])
+			i += end
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
 }
 
 // FilterCommitsByAuthor filters commits by author name
@@ -275,52 +722,271 @@ func FilterCommitsByDate(commits []Commit, filter *DateFilter) []Commit {
 	return filtered
 }
 
-func CherryPickCommits(repoDir string, commitHashes []string) error {
-	if len(commitHashes) == 0 {
-		return nil
+// PickStatus describes what happened to a single commit during a cherry-pick
+type PickStatus string
+
+const (
+	PickStatusPicked         PickStatus = "picked"
+	PickStatusAlreadyApplied PickStatus = "already_applied"
+	PickStatusConflict       PickStatus = "conflict"
+	PickStatusPending        PickStatus = "pending"
+)
+
+// Label returns the status as shown to people, e.g. "already applied"
+func (s PickStatus) Label() string {
+	return strings.ReplaceAll(string(s), "_", " ")
+}
+
+// UnmarshalJSON reads a status, accepting "empty", the name sessions recorded
+// before it was renamed to already_applied
+func (s *PickStatus) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if value == "empty" {
+		value = string(PickStatusAlreadyApplied)
+	}
+	*s = PickStatus(value)
+	return nil
+}
+
+// PickResult maps a source commit to the commit it produced on the target branch.
+// NewHash is set for picked commits and for already-applied ones kept as empty commits.
+type PickResult struct {
+	Source  string     `json:"source"`
+	NewHash string     `json:"new_hash,omitempty"`
+	Status  PickStatus `json:"status"`
+}
+
+// Empty policies: what to do with a commit whose changes are already on the target
+const (
+	EmptySkip = "skip" // Drop it and go on
+	EmptyKeep = "keep" // Record it as an empty commit (git cherry-pick --keep-redundant-commits)
+	EmptyStop = "stop" // Stop with the cherry-pick in progress, like a conflict
+)
+
+// PickOptions controls how CherryPickCommits applies commits
+type PickOptions struct {
+	// Mainline is the parent number merges are picked relative to (git cherry-pick -m)
+	Mainline int
+	// Empty is the empty policy; an empty string means EmptySkip
+	Empty string
+}
+
+// CherryPickCommits applies the commits one at a time in the given order (oldest first).
+// Commits whose changes are already on the target are handled by the empty policy. On a
+// conflict the pick stops with a ConflictError: the conflicting commit is left in progress
+// and the rest stay pending. Once ctx is cancelled the commit being picked is finished and
+// the pick stops with an InterruptedError, the rest pending. The results are returned either way.
+func CherryPickCommits(ctx context.Context, repoDir string, commits []Commit, opts PickOptions) ([]PickResult, error) {
+	if len(commits) == 0 {
+		return nil, nil
 	}
 
-	fmt.Printf("Cherry-picking %d commits...\n", len(commitHashes))
+	fmt.Printf("Cherry-picking %d commits...\n", len(commits))
 
-	oldestCommit := commitHashes[len(commitHashes)-1]
-	newestCommit := commitHashes[0]
-	commitRange := fmt.Sprintf("%s^..%s", oldestCommit, newestCommit)
+	// A commit is picked to the end even if ctx is cancelled meanwhile
+	step := context.WithoutCancel(ctx)
 
-	revListCmd := exec.Command("git", "rev-list", "--reverse", commitRange)
-	revListCmd.Dir = repoDir
-	revListOutput, err := revListCmd.Output()
-	if err != nil {
-		return fmt.Errorf("failed to get commit range: %v", err)
+	results := make([]PickResult, 0, len(commits))
+	markPending := func(rest []Commit) {
+		for _, pending := range rest {
+			results = append(results, PickResult{Source: pending.Hash, Status: PickStatusPending})
+		}
 	}
 
-	cherryPickCmd := exec.Command("git", "cherry-pick", "--stdin")
-	cherryPickCmd.Dir = repoDir
-	cherryPickCmd.Stdin = strings.NewReader(string(revListOutput))
+	for i, commit := range commits {
+		if ctx.Err() != nil {
+			markPending(commits[i:])
+			return results, &InterruptedError{Pending: len(commits) - i}
+		}
 
-	if err := cherryPickCmd.Run(); err != nil {
-		statusCmd := exec.Command("git", "status", "--porcelain")
-		statusCmd.Dir = repoDir
-		statusOutput, _ := statusCmd.Output()
+		result, err := cherryPickOne(step, repoDir, commit, opts)
+		if err != nil {
+			// The failed commit is left to pick again with the rest
+			markPending(commits[i:])
+			return results, err
+		}
+		results = append(results, result)
 
-		fmt.Println("\nConflicts found - needs to be resolved.")
+		stopped := result.Status == PickStatusAlreadyApplied && opts.Empty == EmptyStop
+		if result.Status == PickStatusConflict || stopped {
+			markPending(commits[i+1:])
+			if stopped {
+				printStoppedHelp(commit)
+				return results, &AlreadyAppliedError{Commit: commit.Hash}
+			}
+			printConflictHelp(step, repoDir)
+			return results, &ConflictError{Commit: commit.Hash}
+		}
+	}
 
-		if len(statusOutput) > 0 {
-			fmt.Printf("\nFiles with conflicts:\n%s", string(statusOutput))
+	return results, nil
+}
+
+// cherryPickOne applies a single commit and reports how it went
+func cherryPickOne(ctx context.Context, repoDir string, commit Commit, opts PickOptions) (PickResult, error) {
+	hash := commit.Hash
+	result := PickResult{Source: hash}
+
+	args := []string{"cherry-pick"}
+	if opts.Empty == EmptyKeep {
+		args = append(args, "--keep-redundant-commits")
+	}
+	if commit.IsMerge() {
+		if opts.Mainline < 1 {
+			return result, fmt.Errorf("%s is a merge commit; pick it with a mainline parent (--merges mainline)", hash)
 		}
+		args = append(args, "-m", strconv.Itoa(opts.Mainline))
+	}
+	args = append(args, hash)
 
-		fmt.Println("\nWhat to do:")
-		fmt.Println("1. Resolve the conflicts in the files listed above")
-		fmt.Println("2. Add the resolved files: git add <file>")
-		fmt.Println("3. Continue: chr pick --continue")
-		fmt.Println("4. Or abort: git cherry-pick --abort")
+	if _, err := runDetached(ctx, repoDir, args...); err != nil {
+		if !CherryPickInProgress(ctx, repoDir) {
+			return result, fmt.Errorf("failed to cherry-pick %s: %w", hash, err)
+		}
 
-		return nil
+		if !hasStagedOrConflictedChanges(ctx, repoDir) {
+			result.Status = PickStatusAlreadyApplied
+			if opts.Empty == EmptyStop {
+				return result, nil
+			}
+			if err := SkipCherryPick(ctx, repoDir); err != nil {
+				return result, err
+			}
+			return result, nil
+		}
+
+		result.Status = PickStatusConflict
+		return result, nil
+	}
+
+	newHash, err := GetCommitHash(ctx, repoDir, "HEAD")
+	if err != nil {
+		return result, err
+	}
+	result.NewHash = newHash
+	result.Status = PickStatusPicked
+	if opts.Empty == EmptyKeep && isEmptyCommit(ctx, repoDir, "HEAD") {
+		result.Status = PickStatusAlreadyApplied
+	}
+	return result, nil
+}
+
+// CherryPickIsEmpty reports whether the cherry-pick in progress has nothing left to
+// commit, i.e. its changes are already on the target
+func CherryPickIsEmpty(ctx context.Context, repoDir string) bool {
+	return CherryPickInProgress(ctx, repoDir) && !hasStagedOrConflictedChanges(ctx, repoDir)
+}
+
+// SkipCherryPick drops the commit currently being cherry-picked
+func SkipCherryPick(ctx context.Context, repoDir string) error {
+	if _, err := runDetached(ctx, repoDir, "cherry-pick", "--skip"); err != nil {
+		return fmt.Errorf("failed to skip cherry-pick: %w", err)
+	}
+	return nil
+}
+
+// AbortCherryPick cancels the cherry-pick in progress, restoring the branch to
+// where it was before the commit being picked
+func AbortCherryPick(ctx context.Context, repoDir string) error {
+	if _, err := runDetached(ctx, repoDir, "cherry-pick", "--abort"); err != nil {
+		return fmt.Errorf("failed to abort cherry-pick: %w", err)
 	}
+	return nil
+}
 
-	fmt.Println("✓ Successfully cherry-picked all commits!")
+// ResetHard moves the current branch to ref, discarding the commits after it and
+// any changes in the working tree
+func ResetHard(ctx context.Context, repoDir, ref string) error {
+	if _, err := runDetached(ctx, repoDir, "reset", "--hard", "--quiet", ref); err != nil {
+		return fmt.Errorf("failed to reset to %s: %w", ref, err)
+	}
 	return nil
 }
 
+// IsAncestor reports whether ancestor is reachable from ref
+func IsAncestor(ctx context.Context, repoDir, ancestor, ref string) (bool, error) {
+	_, err := run(ctx, repoDir, "merge-base", "--is-ancestor", ancestor, ref)
+	var cmdErr *CommandError
+	if errors.As(err, &cmdErr) && cmdErr.ExitCode == 1 {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to compare %s with %s: %w", ancestor, ref, err)
+	}
+	return true, nil
+}
+
+// ContinueCherryPick commits the resolved cherry-pick in progress with its original
+// message. While conflicts remain it lists them and returns a ConflictError.
+func ContinueCherryPick(ctx context.Context, repoDir string) error {
+	_, err := RunnerFrom(ctx).Run(ctx, Command{
+		Dir:      repoDir,
+		Args:     []string{"cherry-pick", "--continue"},
+		Env:      []string{"GIT_EDITOR=true"},
+		Detached: true,
+	})
+	if err == nil {
+		return nil
+	}
+	if commit, headErr := GetCommitHash(ctx, repoDir, "CHERRY_PICK_HEAD"); headErr == nil {
+		printConflictHelp(ctx, repoDir)
+		return &ConflictError{Commit: commit}
+	}
+	return fmt.Errorf("cherry-pick continue failed: %w", err)
+}
+
+// isEmptyCommit reports whether a commit has the same tree as its first parent
+func isEmptyCommit(ctx context.Context, repoDir, ref string) bool {
+	_, err := run(ctx, repoDir, "diff", "--quiet", ref+"^", ref)
+	return err == nil
+}
+
+// hasStagedOrConflictedChanges reports whether tracked files differ from HEAD,
+// which tells a real conflict apart from a cherry-pick that turned out empty
+func hasStagedOrConflictedChanges(ctx context.Context, repoDir string) bool {
+	output, err := run(ctx, repoDir, "status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return true
+	}
+	return len(strings.TrimSpace(string(output))) > 0
+}
+
+// printStoppedHelp explains a pick stopped on an already-applied commit
+func printStoppedHelp(commit Commit) {
+	fmt.Printf("\nStopped: %s (%s) is already applied on the target.\n", commit.Hash, commit.Message)
+	fmt.Println("\nWhat to do:")
+	fmt.Println("1. Skip it and pick the rest: chr pick --continue")
+	fmt.Println("2. Or undo the whole pick: chr pick --abort")
+}
+
+// printConflictHelp lists the conflicting files and how to move on
+func printConflictHelp(ctx context.Context, repoDir string) {
+	statusOutput, _ := run(ctx, repoDir, "status", "--porcelain")
+
+	fmt.Println("\nConflicts found - needs to be resolved.")
+
+	if len(statusOutput) > 0 {
+		fmt.Printf("\nFiles with conflicts:\n%s", string(statusOutput))
+	}
+
+	fmt.Println("\nWhat to do:")
+	fmt.Println("1. Resolve the conflicts in the files listed above")
+	fmt.Println("2. Add the resolved files: git add <file>")
+	fmt.Println("3. Continue: chr pick --continue")
+	fmt.Println("4. Or undo the whole pick: chr pick --abort")
+}
+
+// FullMessage returns the subject and body as a single commit message
+func (c Commit) FullMessage() string {
+	if c.Body == "" {
+		return c.Message
+	}
+	return c.Message + "\n\n" + c.Body
+}
+
 // Signature creates a unique signature for a commit (for rebase-safe comparison)
 func (c Commit) Signature() string {
 	return fmt.Sprintf("%s:%s:%s", c.Author, c.Date, strings.Split(c.Message, "\n")[0])
@@ -345,10 +1011,8 @@ func ParseBranchName(branchName, prefix, suffix string) (string, error) {
 	return branchIdentifier, nil
 }
 
-func FindBranchByPattern(repoDir, pattern string) (string, error) {
-	cmd := exec.Command("git", "for-each-ref", "--format=%(refname:short)", fmt.Sprintf("refs/heads/%s", pattern))
-	cmd.Dir = repoDir
-	output, err := cmd.Output()
+func FindBranchByPattern(ctx context.Context, repoDir, pattern string) (string, error) {
+	output, err := run(ctx, repoDir, "for-each-ref", "--format=%(refname:short)", fmt.Sprintf("refs/heads/%s", pattern))
 	if err != nil {
 		return "", fmt.Errorf("failed to search for branches matching pattern '%s': %w", pattern, err)
 	}
@@ -1,13 +1,18 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/carlosarraes/chr/internal/logging"
 )
 
 // setupTestRepo creates a test git repository with test commits
@@ -83,7 +88,7 @@ func TestGetCurrentBranch(t *testing.T) {
 	expectedBranch := "ZUP-123-test"
 	createTestCommit(t, repoDir, expectedBranch, "test commit")
 
-	branch, err := GetCurrentBranch(repoDir)
+	branch, err := GetCurrentBranch(context.Background(), repoDir)
 	if err != nil {
 		t.Fatalf("GetCurrentBranch failed: %v", err)
 	}
@@ -96,7 +101,7 @@ func TestGetCurrentBranch(t *testing.T) {
 func TestGetCurrentUser(t *testing.T) {
 	repoDir := setupTestRepo(t)
 
-	user, err := GetCurrentUser(repoDir)
+	user, err := GetCurrentUser(context.Background(), repoDir)
 	if err != nil {
 		t.Fatalf("GetCurrentUser failed: %v", err)
 	}
@@ -115,7 +120,7 @@ func TestBranchExists(t *testing.T) {
 	createTestCommit(t, repoDir, testBranch, "test commit")
 
 	// Test existing branch
-	exists, err := BranchExists(repoDir, testBranch)
+	exists, err := BranchExists(context.Background(), repoDir, testBranch)
 	if err != nil {
 		t.Fatalf("BranchExists failed: %v", err)
 	}
@@ -124,7 +129,7 @@ func TestBranchExists(t *testing.T) {
 	}
 
 	// Test non-existing branch
-	exists, err = BranchExists(repoDir, "non-existent-branch")
+	exists, err = BranchExists(context.Background(), repoDir, "non-existent-branch")
 	if err != nil {
 		t.Fatalf("BranchExists failed: %v", err)
 	}
@@ -158,7 +163,7 @@ func TestGetCommits(t *testing.T) {
 	createTestCommit(t, repoDir, hmlBranch, "commit 1 in hml")
 
 	// Get commits that are in PRD but not in HML
-	commits, err := GetCommits(repoDir, hmlBranch, prdBranch, 10, false)
+	commits, err := GetCommits(context.Background(), repoDir, hmlBranch, prdBranch, 10, logging.Discard())
 	if err != nil {
 		t.Fatalf("GetCommits failed: %v", err)
 	}
@@ -204,7 +209,7 @@ func TestGetCommitsWithLimit(t *testing.T) {
 	createTestCommit(t, repoDir, prdBranch, "commit 5")
 
 	// Get only 2 commits
-	commits, err := GetCommits(repoDir, hmlBranch, prdBranch, 2, false)
+	commits, err := GetCommits(context.Background(), repoDir, hmlBranch, prdBranch, 2, logging.Discard())
 	if err != nil {
 		t.Fatalf("GetCommits failed: %v", err)
 	}
@@ -392,7 +397,7 @@ func TestFindBranchByPattern(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			branch, err := FindBranchByPattern(repoDir, tt.pattern)
+			branch, err := FindBranchByPattern(context.Background(), repoDir, tt.pattern)
 
 			if tt.expectError {
 				if err == nil {
@@ -440,38 +445,783 @@ func TestCherryPickCommits(t *testing.T) {
 
 	createTestCommit(t, repoDir, hmlBranch, "hml base commit")
 
-	// Cherry-pick commits to HML
-	commitHashes := []string{commit1, commit2}
-	err := CherryPickCommits(repoDir, commitHashes)
+	// Cherry-pick commits to HML, oldest first
+	results, err := CherryPickCommits(context.Background(), repoDir, []Commit{{Hash: commit1}, {Hash: commit2}}, PickOptions{})
 	if err != nil {
 		t.Fatalf("CherryPickCommits failed with unexpected error: %v", err)
 	}
 
-	cherryPickHeadPath := fmt.Sprintf("%s/.git/CHERRY_PICK_HEAD", repoDir)
-	if _, statErr := os.Stat(cherryPickHeadPath); statErr == nil {
-		t.Logf("Cherry-pick encountered conflicts as expected")
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	for i, source := range []string{commit1, commit2} {
+		if results[i].Source != source {
+			t.Errorf("Result %d: expected source %s, got %s", i, source, results[i].Source)
+		}
+		if results[i].Status != PickStatusPicked {
+			t.Errorf("Result %d: expected status picked, got %s", i, results[i].Status)
+		}
+	}
 
-		cmd := exec.Command("git", "cherry-pick", "--abort")
-		cmd.Dir = repoDir
-		_ = cmd.Run()
+	head, err := GetCommitHash(context.Background(), repoDir, "HEAD")
+	if err != nil {
+		t.Fatalf("Failed to resolve HEAD: %v", err)
+	}
+	if results[1].NewHash != head {
+		t.Errorf("Expected last new hash to be HEAD %s, got %s", head, results[1].NewHash)
+	}
+
+}
+
+func TestCherryPickCommits_Failed(t *testing.T) {
+	repoDir := setupTestRepo(t)
+	createTestCommit(t, repoDir, "main", "base commit")
+	commit1 := createTestCommit(t, repoDir, "ZUP-123-prd", "commit 1")
+	commit2 := createTestCommit(t, repoDir, "ZUP-123-prd", "commit 2")
+	runGit(t, repoDir, "checkout", "main")
+
+	missing := "0123456789abcdef0123456789abcdef01234567"
+	commits := []Commit{{Hash: commit1}, {Hash: missing}, {Hash: commit2}}
+	results, err := CherryPickCommits(context.Background(), repoDir, commits, PickOptions{})
+	if err == nil || IsPickStopped(err) {
+		t.Fatalf("Expected a failed pick, got %v", err)
+	}
+
+	want := []PickStatus{PickStatusPicked, PickStatusPending, PickStatusPending}
+	if len(results) != len(want) {
+		t.Fatalf("Expected %d results, got %+v", len(want), results)
+	}
+	for i, status := range want {
+		if results[i].Source != commits[i].Hash || results[i].Status != status {
+			t.Errorf("Result %d: expected %s %s, got %+v", i, commits[i].Hash, status, results[i])
+		}
+	}
+}
+
+func TestCherryPickCommits_EmptyPolicies(t *testing.T) {
+	repoDir := setupTestRepo(t)
+
+	createTestCommit(t, repoDir, "main", "base commit")
+	runGit(t, repoDir, "checkout", "-b", "target")
+	applied := createTestCommit(t, repoDir, "source", "applied change")
+	later := createTestCommit(t, repoDir, "source", "later change")
+	runGit(t, repoDir, "checkout", "target")
+	runGit(t, repoDir, "cherry-pick", applied)
+	commits := []Commit{{Hash: applied, Message: "applied change"}, {Hash: later, Message: "later change"}}
+
+	// skip: the applied commit is dropped and the rest is picked
+	runGit(t, repoDir, "checkout", "-b", "skip", "target")
+	results, err := CherryPickCommits(context.Background(), repoDir, commits, PickOptions{Empty: EmptySkip})
+	if err != nil {
+		t.Fatalf("CherryPickCommits failed: %v", err)
+	}
+	if results[0].Status != PickStatusAlreadyApplied || results[0].NewHash != "" || results[1].Status != PickStatusPicked {
+		t.Errorf("skip: unexpected results %+v", results)
+	}
+	if CherryPickInProgress(context.Background(), repoDir) {
+		t.Error("skip: expected no cherry-pick in progress")
+	}
+
+	// keep: an empty commit records the applied one
+	runGit(t, repoDir, "checkout", "-b", "keep", "target")
+	results, err = CherryPickCommits(context.Background(), repoDir, commits, PickOptions{Empty: EmptyKeep})
+	if err != nil {
+		t.Fatalf("CherryPickCommits failed: %v", err)
+	}
+	if results[0].Status != PickStatusAlreadyApplied || results[0].NewHash == "" || results[1].Status != PickStatusPicked {
+		t.Errorf("keep: unexpected results %+v", results)
+	}
 
-		return
+	// stop: the pick stops on the applied commit
+	runGit(t, repoDir, "checkout", "-b", "stop", "target")
+	results, err = CherryPickCommits(context.Background(), repoDir, commits, PickOptions{Empty: EmptyStop})
+	var stopped *AlreadyAppliedError
+	if !errors.As(err, &stopped) || stopped.Commit != applied || !IsPickStopped(err) {
+		t.Fatalf("Expected an AlreadyAppliedError, got %v", err)
+	}
+	if results[0].Status != PickStatusAlreadyApplied || results[1].Status != PickStatusPending {
+		t.Errorf("stop: unexpected results %+v", results)
+	}
+	if !CherryPickIsEmpty(context.Background(), repoDir) {
+		t.Fatal("stop: expected an empty cherry-pick in progress")
+	}
+	if err := SkipCherryPick(context.Background(), repoDir); err != nil {
+		t.Fatalf("SkipCherryPick failed: %v", err)
+	}
+	if CherryPickInProgress(context.Background(), repoDir) {
+		t.Error("stop: expected the cherry-pick to be skipped")
+	}
+}
+
+func TestCherryPickCommits_Conflict(t *testing.T) {
+	repoDir := setupTestRepo(t)
+	writeAndCommit := func(content, message string) string {
+		if err := os.WriteFile(filepath.Join(repoDir, "shared.txt"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+		runGit(t, repoDir, "add", ".")
+		runGit(t, repoDir, "commit", "-m", message)
+		hash, err := GetCommitHash(context.Background(), repoDir, "HEAD")
+		if err != nil {
+			t.Fatalf("Failed to resolve HEAD: %v", err)
+		}
+		return hash
 	}
 
-	// Verify commits were cherry-picked by checking log
-	cmd = exec.Command("git", "log", "--oneline", "-n", "5")
+	base := writeAndCommit("base\n", "base")
+	runGit(t, repoDir, "checkout", "-b", "source")
+	conflicting := writeAndCommit("source\n", "source change")
+	later := createTestCommit(t, repoDir, "source", "later change")
+	runGit(t, repoDir, "checkout", "-b", "target", base)
+	writeAndCommit("target\n", "target change")
+
+	results, err := CherryPickCommits(context.Background(), repoDir, []Commit{{Hash: conflicting}, {Hash: later}}, PickOptions{})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || conflict.Commit != conflicting {
+		t.Fatalf("Expected a ConflictError for %s, got %v", conflicting, err)
+	}
+	defer runGit(t, repoDir, "cherry-pick", "--abort")
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if results[0].Status != PickStatusConflict {
+		t.Errorf("Expected first commit to conflict, got %s", results[0].Status)
+	}
+	if results[1].Status != PickStatusPending {
+		t.Errorf("Expected second commit to stay pending, got %s", results[1].Status)
+	}
+	if !CherryPickInProgress(context.Background(), repoDir) {
+		t.Error("Expected the conflicting cherry-pick to be left in progress")
+	}
+}
+
+// cancelAfterPick cancels a context once the first cherry-pick has run, like a
+// Ctrl-C arriving while a commit is being picked
+type cancelAfterPick struct {
+	next   Runner
+	cancel context.CancelFunc
+}
+
+func (r *cancelAfterPick) Run(ctx context.Context, c Command) ([]byte, error) {
+	output, err := r.next.Run(ctx, c)
+	if len(c.Args) > 0 && c.Args[0] == "cherry-pick" {
+		r.cancel()
+	}
+	return output, err
+}
+
+func TestCherryPickCommits_Interrupted(t *testing.T) {
+	repoDir := setupTestRepo(t)
+	createTestCommit(t, repoDir, "main", "base commit")
+	commit1 := createTestCommit(t, repoDir, "source", "commit 1")
+	commit2 := createTestCommit(t, repoDir, "source", "commit 2")
+	commit3 := createTestCommit(t, repoDir, "source", "commit 3")
+	runGit(t, repoDir, "checkout", "-b", "target", "main")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = WithRunner(ctx, &cancelAfterPick{next: RunnerFrom(ctx), cancel: cancel})
+
+	results, err := CherryPickCommits(ctx, repoDir, []Commit{{Hash: commit1}, {Hash: commit2}, {Hash: commit3}}, PickOptions{})
+	var interrupted *InterruptedError
+	if !errors.As(err, &interrupted) || interrupted.Pending != 2 {
+		t.Fatalf("Expected an InterruptedError with 2 pending, got %v", err)
+	}
+	if !errors.Is(err, context.Canceled) || !IsPickStopped(err) {
+		t.Errorf("Expected the interruption to be a resumable cancellation, got %v", err)
+	}
+
+	want := []PickStatus{PickStatusPicked, PickStatusPending, PickStatusPending}
+	if len(results) != len(want) {
+		t.Fatalf("Expected %d results, got %d", len(want), len(results))
+	}
+	for i, status := range want {
+		if results[i].Status != status {
+			t.Errorf("Result %d: expected %s, got %s", i, status, results[i].Status)
+		}
+	}
+
+	// The commit being picked when the cancel came is finished, nothing is left in progress
+	head, err := GetCommitHash(context.Background(), repoDir, "HEAD")
+	if err != nil {
+		t.Fatalf("Failed to resolve HEAD: %v", err)
+	}
+	if results[0].NewHash != head {
+		t.Errorf("Expected the first commit to be HEAD %s, got %s", head, results[0].NewHash)
+	}
+	if CherryPickInProgress(context.Background(), repoDir) {
+		t.Error("Expected no cherry-pick in progress after an interruption")
+	}
+}
+
+func TestAbortAndResetHard(t *testing.T) {
+	repoDir := setupTestRepo(t)
+	ctx := context.Background()
+	base := createTestCommit(t, repoDir, "main", "base commit")
+	picked := createTestCommit(t, repoDir, "main", "picked commit")
+
+	if ok, err := IsAncestor(ctx, repoDir, base, "HEAD"); err != nil || !ok {
+		t.Fatalf("IsAncestor(base, HEAD) = %v, %v; want true", ok, err)
+	}
+	if ok, err := IsAncestor(ctx, repoDir, picked, base); err != nil || ok {
+		t.Fatalf("IsAncestor(picked, base) = %v, %v; want false", ok, err)
+	}
+
+	if err := ResetHard(ctx, repoDir, base); err != nil {
+		t.Fatalf("ResetHard failed: %v", err)
+	}
+	head, err := GetCommitHash(ctx, repoDir, "HEAD")
+	if err != nil {
+		t.Fatalf("Failed to resolve HEAD: %v", err)
+	}
+	if head != base {
+		t.Errorf("Expected HEAD to be back at %s, got %s", base, head)
+	}
+}
+
+func TestMergeCommits(t *testing.T) {
+	repoDir := setupTestRepo(t)
+
+	createTestCommit(t, repoDir, "main", "base commit")
+	runGit(t, repoDir, "checkout", "-b", "target")
+	runGit(t, repoDir, "checkout", "-b", "source")
+	direct := createTestCommit(t, repoDir, "source", "direct commit")
+	runGit(t, repoDir, "checkout", "-b", "feature")
+	featureCommit := createTestCommit(t, repoDir, "feature", "feature commit")
+	runGit(t, repoDir, "checkout", "source")
+	runGit(t, repoDir, "merge", "--no-ff", "-m", "Merge feature", "feature")
+
+	commits, err := GetCommits(context.Background(), repoDir, "target", "source", 0, logging.Discard())
+	if err != nil {
+		t.Fatalf("GetCommits failed: %v", err)
+	}
+	if len(commits) != 3 {
+		t.Fatalf("Expected merge, feature and direct commits, got %+v", commits)
+	}
+	merge := commits[0]
+	if !merge.IsMerge() || merge.Message != "Merge feature" {
+		t.Fatalf("Expected newest commit to be the merge, got %+v", merge)
+	}
+
+	firstParent, err := GetFirstParentHashes(context.Background(), repoDir, "target", "source")
+	if err != nil {
+		t.Fatalf("GetFirstParentHashes failed: %v", err)
+	}
+	if len(firstParent) != 2 || !firstParent[merge.Hash] || !firstParent[direct[:len(merge.Hash)]] {
+		t.Errorf("Expected merge and direct commit on the first-parent chain, got %v", firstParent)
+	}
+
+	merged, err := GetMergedCommits(context.Background(), repoDir, merge.Hash)
+	if err != nil {
+		t.Fatalf("GetMergedCommits failed: %v", err)
+	}
+	if len(merged) != 1 || !strings.HasPrefix(featureCommit, merged[0]) {
+		t.Errorf("Expected only the feature commit, got %v", merged)
+	}
+
+	runGit(t, repoDir, "checkout", "target")
+	if _, err := CherryPickCommits(context.Background(), repoDir, []Commit{merge}, PickOptions{}); err == nil {
+		t.Error("Expected picking a merge without a mainline to fail")
+	}
+	results, err := CherryPickCommits(context.Background(), repoDir, []Commit{merge}, PickOptions{Mainline: 1})
+	if err != nil {
+		t.Fatalf("CherryPickCommits failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Status != PickStatusPicked {
+		t.Errorf("Expected the merge to be picked with -m 1, got %+v", results)
+	}
+}
+
+func TestCheckRepository(t *testing.T) {
+	if err := CheckRepository(context.Background(), setupTestRepo(t)); err != nil {
+		t.Errorf("Expected a repository, got %v", err)
+	}
+	if err := CheckRepository(context.Background(), t.TempDir()); !errors.Is(err, ErrNotRepository) {
+		t.Errorf("Expected ErrNotRepository, got %v", err)
+	}
+}
+
+func TestCheckCleanTree(t *testing.T) {
+	repoDir := setupTestRepo(t)
+	createTestCommit(t, repoDir, "main", "base commit")
+
+	// Untracked files don't get in the way of a cherry-pick
+	if err := os.WriteFile(filepath.Join(repoDir, "untracked.txt"), []byte("x"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := CheckCleanTree(context.Background(), repoDir); err != nil {
+		t.Errorf("Expected a clean tree, got %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(repoDir, "tracked.txt"), []byte("x"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	runGit(t, repoDir, "add", "tracked.txt")
+
+	var dirty *DirtyTreeError
+	if err := CheckCleanTree(context.Background(), repoDir); !errors.As(err, &dirty) || len(dirty.Files) != 1 || dirty.Files[0] != "tracked.txt" {
+		t.Errorf("Expected a DirtyTreeError for tracked.txt, got %v", err)
+	}
+}
+
+// runGit runs a git command in repoDir and fails the test on error
+func runGit(t testing.TB, repoDir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
 	cmd.Dir = repoDir
-	output, err := cmd.Output()
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+}
+
+// setupRemote creates a bare repository and registers it as origin of repoDir
+func setupRemote(t *testing.T, repoDir string) string {
+	remoteDir := t.TempDir()
+
+	cmd := exec.Command("git", "init", "--bare")
+	cmd.Dir = remoteDir
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to init bare repo: %v", err)
+	}
+
+	cmd = exec.Command("git", "remote", "add", "origin", remoteDir)
+	cmd.Dir = repoDir
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to add remote: %v", err)
+	}
+
+	return remoteDir
+}
+
+func TestGetAheadBehind(t *testing.T) {
+	repoDir := setupTestRepo(t)
+
+	createTestCommit(t, repoDir, "main", "base commit")
+	createTestCommit(t, repoDir, "ZUP-123-hml", "hml commit 1")
+	createTestCommit(t, repoDir, "ZUP-123-hml", "hml commit 2")
+
+	cmd := exec.Command("git", "checkout", "main")
+	cmd.Dir = repoDir
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to checkout main: %v", err)
+	}
+	createTestCommit(t, repoDir, "main", "main commit")
+
+	ahead, behind, err := GetAheadBehind(context.Background(), repoDir, "ZUP-123-hml", "main")
 	if err != nil {
-		t.Fatalf("Failed to get git log: %v", err)
+		t.Fatalf("GetAheadBehind failed: %v", err)
+	}
+	if ahead != 2 || behind != 1 {
+		t.Errorf("Expected 2 ahead and 1 behind, got %d ahead and %d behind", ahead, behind)
+	}
+}
+
+func TestPushBranch(t *testing.T) {
+	repoDir := setupTestRepo(t)
+	setupRemote(t, repoDir)
+
+	createTestCommit(t, repoDir, "main", "base commit")
+	head := createTestCommit(t, repoDir, "ZUP-123-hml", "hml commit")
+
+	sha, err := PushBranch(context.Background(), repoDir, "origin", "ZUP-123-hml", false)
+	if err != nil {
+		t.Fatalf("PushBranch failed: %v", err)
+	}
+	if sha != head {
+		t.Errorf("Expected pushed sha %s, got %s", head, sha)
+	}
+
+	// Rewrite local history so the next push is rejected
+	cmd := exec.Command("git", "reset", "--hard", "main")
+	cmd.Dir = repoDir
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to reset branch: %v", err)
+	}
+	createTestCommit(t, repoDir, "ZUP-123-hml", "rewritten commit")
+
+	_, err = PushBranch(context.Background(), repoDir, "origin", "ZUP-123-hml", false)
+	if err == nil {
+		t.Fatal("Expected rejected push to fail")
+	}
+	if !strings.Contains(err.Error(), "1 ahead and 1 behind") {
+		t.Errorf("Expected ahead/behind in error, got: %v", err)
 	}
 
-	logOutput := string(output)
-	t.Logf("Git log output: %s", logOutput)
+	if _, err := PushBranch(context.Background(), repoDir, "origin", "ZUP-123-hml", true); err != nil {
+		t.Errorf("Expected force-with-lease push to succeed, got: %v", err)
+	}
+}
 
-	if !strings.Contains(logOutput, "commit 1") && !strings.Contains(logOutput, "commit 2") {
-		t.Logf("Cherry-picked commits not explicitly found in log, but cherry-pick process completed successfully")
-	} else {
-		t.Logf("Cherry-picked commits found in log")
+func TestFetchBranches(t *testing.T) {
+	upstreamDir := setupTestRepo(t)
+	createTestCommit(t, upstreamDir, "main", "base commit")
+	createTestCommit(t, upstreamDir, "ZUP-123-prd", "prd commit")
+	createTestCommit(t, upstreamDir, "ZUP-123-hml", "hml commit")
+	createTestCommit(t, upstreamDir, "ZUP-999-prd", "unrelated commit")
+
+	repoDir := setupTestRepo(t)
+	cmd := exec.Command("git", "remote", "add", "upstream", upstreamDir)
+	cmd.Dir = repoDir
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to add remote: %v", err)
+	}
+
+	if err := FetchBranches(context.Background(), repoDir, "upstream", logging.Discard(), "ZUP-123-prd", "ZUP-123-hml", "ZUP-123-missing"); err != nil {
+		t.Fatalf("FetchBranches failed: %v", err)
+	}
+
+	for _, ref := range []string{"upstream/ZUP-123-prd", "upstream/ZUP-123-hml"} {
+		if exists, _ := BranchExists(context.Background(), repoDir, ref); !exists {
+			t.Errorf("Expected %s to be fetched", ref)
+		}
+	}
+	if exists, _ := BranchExists(context.Background(), repoDir, "upstream/ZUP-999-prd"); exists {
+		t.Error("Expected unrelated branch not to be fetched")
+	}
+
+	if ref := ResolveRef(context.Background(), repoDir, "upstream", "ZUP-123-prd", logging.Discard()); ref != "upstream/ZUP-123-prd" {
+		t.Errorf("Expected remote ref to be used, got %q", ref)
+	}
+
+	// A missing remote is not an error
+	if err := FetchBranches(context.Background(), repoDir, "nowhere", logging.Discard(), "ZUP-123-prd"); err != nil {
+		t.Errorf("Expected missing remote to be skipped, got: %v", err)
+	}
+}
+
+func TestGetSyncStatus(t *testing.T) {
+	upstreamDir := setupTestRepo(t)
+	createTestCommit(t, upstreamDir, "ZUP-123-hml", "hml commit 1")
+
+	repoDir := t.TempDir()
+	cmd := exec.Command("git", "clone", "--quiet", upstreamDir, repoDir)
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to clone: %v", err)
+	}
+
+	createTestCommit(t, upstreamDir, "ZUP-123-hml", "hml commit 2")
+	createTestCommit(t, upstreamDir, "ZUP-123-hml", "hml commit 3")
+
+	status, err := GetSyncStatus(context.Background(), repoDir, "origin", "ZUP-123-hml")
+	if err != nil {
+		t.Fatalf("GetSyncStatus failed: %v", err)
+	}
+	if status.Behind != 0 || status.Ahead != 0 {
+		t.Errorf("Expected branch to be up to date before fetch, got %+v", status)
+	}
+
+	if err := FetchBranches(context.Background(), repoDir, "origin", logging.Discard(), "ZUP-123-hml"); err != nil {
+		t.Fatalf("FetchBranches failed: %v", err)
+	}
+
+	status, err = GetSyncStatus(context.Background(), repoDir, "origin", "ZUP-123-hml")
+	if err != nil {
+		t.Fatalf("GetSyncStatus failed: %v", err)
+	}
+	if status.Behind != 2 || status.Diverged() {
+		t.Errorf("Expected branch to be 2 behind without diverging, got %+v", status)
+	}
+
+	for _, args := range [][]string{{"config", "user.name", "Test User"}, {"config", "user.email", "test@example.com"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("Failed to configure clone: %v", err)
+		}
+	}
+	createTestCommit(t, repoDir, "ZUP-123-hml", "local commit")
+
+	status, err = GetSyncStatus(context.Background(), repoDir, "origin", "ZUP-123-hml")
+	if err != nil {
+		t.Fatalf("GetSyncStatus failed: %v", err)
+	}
+	if status.Ahead != 1 || status.Behind != 2 || !status.Diverged() {
+		t.Errorf("Expected branch to diverge 1 ahead and 2 behind, got %+v", status)
+	}
+
+	status, err = GetSyncStatus(context.Background(), repoDir, "origin", "ZUP-404-hml")
+	if err != nil {
+		t.Fatalf("GetSyncStatus failed: %v", err)
+	}
+	if status.HasLocal || status.HasRemote || status.Diverged() {
+		t.Errorf("Expected missing branch to report no state, got %+v", status)
+	}
+}
+
+func TestGetCommits_SubjectAndBody(t *testing.T) {
+	repoDir := setupTestRepo(t)
+
+	createTestCommit(t, repoDir, "main", "base commit")
+	createTestCommit(t, repoDir, "ZUP-123-prd", "feat(api): a | b\n\nBody line.\n\nRefs: ZUP-456")
+
+	commits, err := GetCommits(context.Background(), repoDir, "main", "ZUP-123-prd", 0, logging.Discard())
+	if err != nil {
+		t.Fatalf("GetCommits failed: %v", err)
+	}
+	if len(commits) != 1 {
+		t.Fatalf("Expected 1 commit, got %d", len(commits))
+	}
+	if commits[0].Message != "feat(api): a | b" {
+		t.Errorf("Expected subject with pipe to survive, got %q", commits[0].Message)
+	}
+	if commits[0].Body != "Body line.\n\nRefs: ZUP-456" {
+		t.Errorf("Unexpected body: %q", commits[0].Body)
+	}
+	if commits[0].FullMessage() != "feat(api): a | b\n\nBody line.\n\nRefs: ZUP-456" {
+		t.Errorf("Unexpected full message: %q", commits[0].FullMessage())
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern string
+		file    string
+		match   bool
+	}{
+		{"services/billing/**", "services/billing/api/handler.go", true},
+		{"services/billing/**", "services/billing", true},
+		{"services/billing/**", "services/billingx/a.go", false},
+		{"services/billing", "services/billing/a.go", true},
+		{"services/billing", "services/billing-v2/a.go", false},
+		{"*.go", "cmd/cli.go", true},
+		{"*.go", "README.md", false},
+		{"cmd/*.go", "cmd/cli.go", true},
+		{"cmd/*.go", "cmd/sub/cli.go", false},
+		{"**/testdata/**", "internal/git/testdata/repo.txt", true},
+		{"docs/**/*.md", "docs/a/b/c.md", true},
+		{"docs/**/*.md", "docs/c.md", true},
+		{"file?.txt", "file1.txt", true},
+		{"[ab].txt", "a.txt", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"~"+tt.file, func(t *testing.T) {
+			if got := MatchPath(tt.pattern, tt.file); got != tt.match {
+				t.Errorf("MatchPath(%q, %q) = %v, expected %v", tt.pattern, tt.file, got, tt.match)
+			}
+		})
+	}
+}
+
+func TestFilterCommitsByPath(t *testing.T) {
+	commits := []Commit{
+		{Hash: "a1", Files: []string{"services/billing/invoice.go"}},
+		{Hash: "a2", Files: []string{"services/billing/invoice_test.go", "docs/billing.md"}},
+		{Hash: "a3", Files: []string{"services/users/user.go"}},
+		{Hash: "a4", Files: []string{"docs/readme.md"}},
+	}
+
+	tests := []struct {
+		name     string
+		include  []string
+		exclude  []string
+		expected []string
+	}{
+		{"no patterns", nil, nil, []string{"a1", "a2", "a3", "a4"}},
+		{"include", []string{"services/billing/**"}, nil, []string{"a1", "a2"}},
+		{"exclude", nil, []string{"docs/**"}, []string{"a1", "a2", "a3"}},
+		{"include and exclude", []string{"services/billing/**"}, []string{"*_test.go"}, []string{"a1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hashes []string
+			for _, commit := range FilterCommitsByPath(commits, tt.include, tt.exclude) {
+				hashes = append(hashes, commit.Hash)
+			}
+			if strings.Join(hashes, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected %v, got %v", tt.expected, hashes)
+			}
+		})
+	}
+}
+
+func TestLoadChangedFilesAndGrep(t *testing.T) {
+	repoDir := setupTestRepo(t)
+	createTestCommit(t, repoDir, "main", "base commit")
+	createTestCommit(t, repoDir, "ZUP-123-prd", "feat: billing change")
+	createTestCommit(t, repoDir, "ZUP-123-prd", "fix: other change")
+
+	commits, err := GetCommits(context.Background(), repoDir, "main", "ZUP-123-prd", 0, logging.Discard())
+	if err != nil {
+		t.Fatalf("GetCommits failed: %v", err)
+	}
+	if err := LoadChangedFiles(context.Background(), repoDir, commits); err != nil {
+		t.Fatalf("LoadChangedFiles failed: %v", err)
+	}
+	for _, commit := range commits {
+		if len(commit.Files) != 1 || !strings.HasPrefix(commit.Files[0], "test_ZUP-123-prd_") {
+			t.Errorf("Unexpected files for %s: %v", commit.Hash, commit.Files)
+		}
+	}
+
+	grepped := FilterCommitsByMessage(commits, regexp.MustCompile(`^feat: billing`))
+	if len(grepped) != 1 || grepped[0].Message != "feat: billing change" {
+		t.Errorf("Expected only the billing commit, got %+v", grepped)
+	}
+}
+
+func TestLoadPatchIDs(t *testing.T) {
+	repoDir := setupTestRepo(t)
+	createTestCommit(t, repoDir, "main", "base commit")
+	runGit(t, repoDir, "branch", "ZUP-123-hml")
+	picked := createTestCommit(t, repoDir, "ZUP-123-prd", "feat: billing change")
+	createTestCommit(t, repoDir, "ZUP-123-prd", "fix: other change")
+
+	// Picked to HML under a different message
+	runGit(t, repoDir, "checkout", "ZUP-123-hml")
+	runGit(t, repoDir, "cherry-pick", picked)
+	runGit(t, repoDir, "commit", "--amend", "-m", "Billing change")
+
+	ctx := context.Background()
+	prd, err := GetCommits(ctx, repoDir, "main", "ZUP-123-prd", 0, logging.Discard())
+	if err != nil {
+		t.Fatalf("GetCommits failed: %v", err)
+	}
+	hml, err := GetCommits(ctx, repoDir, "main", "ZUP-123-hml", 0, logging.Discard())
+	if err != nil {
+		t.Fatalf("GetCommits failed: %v", err)
+	}
+	if err := LoadPatchIDs(ctx, repoDir, prd); err != nil {
+		t.Fatalf("LoadPatchIDs failed: %v", err)
+	}
+	if err := LoadPatchIDs(ctx, repoDir, hml); err != nil {
+		t.Fatalf("LoadPatchIDs failed: %v", err)
+	}
+
+	if len(prd) != 2 || len(hml) != 1 {
+		t.Fatalf("Expected 2 PRD and 1 HML commits, got %d and %d", len(prd), len(hml))
+	}
+	for _, c := range append(prd, hml...) {
+		if c.PatchID == "" {
+			t.Errorf("Expected a patch ID for %s", c.Hash)
+		}
+	}
+	// GetCommits lists newest first
+	if prd[1].PatchID != hml[0].PatchID {
+		t.Errorf("Expected the reworded pick to keep patch ID %s, got %s", prd[1].PatchID, hml[0].PatchID)
+	}
+	if prd[0].PatchID == hml[0].PatchID {
+		t.Error("Expected a different change to get a different patch ID")
+	}
+}
+
+func TestNotes(t *testing.T) {
+	repoDir := setupTestRepo(t)
+	first := createTestCommit(t, repoDir, "main", "base commit")
+	second := createTestCommit(t, repoDir, "main", "second commit")
+	ctx := context.Background()
+	const ref = "refs/notes/chr-test"
+
+	notes, err := ListNotes(ctx, repoDir, ref)
+	if err != nil || len(notes) != 0 {
+		t.Fatalf("Expected no notes before the ref exists, got %v, %v", notes, err)
+	}
+
+	if err := AddNote(ctx, repoDir, ref, first, "one\nline two"); err != nil {
+		t.Fatalf("AddNote failed: %v", err)
+	}
+	if err := AddNote(ctx, repoDir, ref, second, "old"); err != nil {
+		t.Fatalf("AddNote failed: %v", err)
+	}
+	if err := AddNote(ctx, repoDir, ref, second, "new"); err != nil {
+		t.Fatalf("AddNote should replace an existing note: %v", err)
+	}
+
+	notes, err = ListNotes(ctx, repoDir, ref)
+	if err != nil {
+		t.Fatalf("ListNotes failed: %v", err)
+	}
+	if notes[first] != "one\nline two\n" || notes[second] != "new\n" || len(notes) != 2 {
+		t.Errorf("Unexpected notes: %q", notes)
+	}
+
+	if err := RemoveNote(ctx, repoDir, ref, first); err != nil {
+		t.Fatalf("RemoveNote failed: %v", err)
+	}
+	if err := RemoveNote(ctx, repoDir, ref, first); err != nil {
+		t.Errorf("Removing a missing note should succeed: %v", err)
+	}
+	notes, err = ListNotes(ctx, repoDir, ref)
+	if err != nil || len(notes) != 1 {
+		t.Errorf("Expected one note left, got %v, %v", notes, err)
+	}
+}
+
+func TestListNotes_SharedBlob(t *testing.T) {
+	repoDir := setupTestRepo(t)
+	first := createTestCommit(t, repoDir, "main", "base commit")
+	second := createTestCommit(t, repoDir, "main", "second commit")
+	ctx := context.Background()
+	const ref = "refs/notes/chr-test"
+
+	// Identical texts are stored as one blob annotating both commits
+	for _, commit := range []string{first, second} {
+		if err := AddNote(ctx, repoDir, ref, commit, "chr: ignored"); err != nil {
+			t.Fatalf("AddNote failed: %v", err)
+		}
+	}
+
+	notes, err := ListNotes(ctx, repoDir, ref)
+	if err != nil {
+		t.Fatalf("ListNotes failed: %v", err)
+	}
+	if len(notes) != 2 || notes[first] != "chr: ignored\n" || notes[second] != "chr: ignored\n" {
+		t.Errorf("Expected both commits to keep their note, got %q", notes)
+	}
+}
+
+func TestGetCommitsByHash(t *testing.T) {
+	repoDir := setupTestRepo(t)
+	first := createTestCommit(t, repoDir, "main", "base commit")
+	second := createTestCommit(t, repoDir, "main", "second commit")
+
+	commits, err := GetCommitsByHash(context.Background(), repoDir, []string{first, second})
+	if err != nil {
+		t.Fatalf("GetCommitsByHash failed: %v", err)
+	}
+	if len(commits) != 2 || commits[0].Message != "base commit" || commits[1].Message != "second commit" {
+		t.Errorf("Expected both commits in the given order, got %+v", commits)
+	}
+}
+
+func TestGetCommitHashes(t *testing.T) {
+	repoDir := setupTestRepo(t)
+	first := createTestCommit(t, repoDir, "main", "base commit")
+	second := createTestCommit(t, repoDir, "main", "second commit")
+	ctx := context.Background()
+
+	hashes, err := GetCommitHashes(ctx, repoDir, []string{second[:7], first})
+	if err != nil {
+		t.Fatalf("GetCommitHashes failed: %v", err)
+	}
+	if len(hashes) != 2 || hashes[0] != second || hashes[1] != first {
+		t.Errorf("Expected the full hashes in the given order, got %v", hashes)
+	}
+
+	if _, err := GetCommitHashes(ctx, repoDir, []string{first, "missing"}); err == nil {
+		t.Error("Expected an unknown commit to fail")
+	}
+}
+
+func TestEnsureConfigValue(t *testing.T) {
+	repoDir := setupTestRepo(t)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := EnsureConfigValue(ctx, repoDir, "notes.rewriteRef", "refs/notes/chr"); err != nil {
+			t.Fatalf("EnsureConfigValue failed: %v", err)
+		}
+	}
+
+	cmd := exec.Command("git", "config", "--get-all", "notes.rewriteRef")
+	cmd.Dir = repoDir
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if got := strings.TrimSpace(string(output)); got != "refs/notes/chr" {
+		t.Errorf("Expected the value once, got %q", got)
 	}
 }
@@ -0,0 +1,427 @@
+package git
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// GoGitBackend answers read-only queries in-process with go-git, saving a git
+// process per call. Repositories are opened once per directory.
+type GoGitBackend struct {
+	mu     sync.Mutex
+	repos  map[string]*gogit.Repository
+	abbrev map[string]int
+}
+
+// NewGoGitBackend creates a go-git backend
+func NewGoGitBackend() *GoGitBackend {
+	return &GoGitBackend{repos: make(map[string]*gogit.Repository), abbrev: make(map[string]int)}
+}
+
+func (b *GoGitBackend) open(repoDir string) (*gogit.Repository, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if repo, ok := b.repos[repoDir]; ok {
+		return repo, nil
+	}
+	repo, err := gogit.PlainOpenWithOptions(repoDir, &gogit.PlainOpenOptions{DetectDotGit: true, EnableDotGitCommonDir: true})
+	if err != nil {
+		return nil, err
+	}
+	b.repos[repoDir] = repo
+	return repo, nil
+}
+
+// abbrevLength returns how many hex digits git abbreviates hashes to, so both
+// backends report the same hashes. Without a numeric core.abbrev git sizes them
+// from the object count; that is asked of git once per repository.
+func (b *GoGitBackend) abbrevLength(ctx context.Context, repoDir string, repo *gogit.Repository) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if length, ok := b.abbrev[repoDir]; ok {
+		return length
+	}
+
+	length := 7
+	cfg, err := repo.ConfigScoped(gitconfig.SystemScope)
+	setting := ""
+	if err == nil {
+		setting = cfg.Raw.Section("core").Option("abbrev")
+	}
+	if n, err := strconv.Atoi(setting); err == nil && n >= 4 {
+		length = min(n, 40)
+	} else if setting == "no" {
+		length = 40
+	} else if output, err := run(ctx, repoDir, "rev-parse", "--short", "HEAD"); err == nil {
+		length = len(strings.TrimSpace(string(output)))
+	}
+
+	b.abbrev[repoDir] = length
+	return length
+}
+
+// CurrentBranch implements Backend
+func (b *GoGitBackend) CurrentBranch(ctx context.Context, repoDir string) (string, error) {
+	repo, err := b.open(repoDir)
+	if err != nil {
+		return "", err
+	}
+	head, err := repo.Storer.Reference(plumbing.HEAD)
+	if err != nil {
+		return "", err
+	}
+	if head.Type() != plumbing.SymbolicReference {
+		return "", nil // Detached HEAD
+	}
+	return head.Target().Short(), nil
+}
+
+// CurrentUser implements Backend
+func (b *GoGitBackend) CurrentUser(ctx context.Context, repoDir string) (string, error) {
+	repo, err := b.open(repoDir)
+	if err != nil {
+		return "", err
+	}
+	cfg, err := repo.ConfigScoped(gitconfig.SystemScope)
+	if err != nil {
+		return "", err
+	}
+	if cfg.User.Name == "" {
+		return "", fmt.Errorf("user.name is not set")
+	}
+	return cfg.User.Name, nil
+}
+
+// ResolveCommit implements Backend
+func (b *GoGitBackend) ResolveCommit(ctx context.Context, repoDir, ref string) (string, error) {
+	repo, err := b.open(repoDir)
+	if err != nil {
+		return "", err
+	}
+	commit, err := b.resolve(repo, ref)
+	if err != nil {
+		return "", err
+	}
+	return commit.Hash.String(), nil
+}
+
+func (b *GoGitBackend) resolve(repo *gogit.Repository, ref string) (*object.Commit, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ref, err)
+	}
+	return repo.CommitObject(*hash)
+}
+
+// AheadBehind implements Backend
+func (b *GoGitBackend) AheadBehind(ctx context.Context, repoDir, localRef, remoteRef string) (int, int, error) {
+	repo, err := b.open(repoDir)
+	if err != nil {
+		return 0, 0, err
+	}
+	ahead, err := b.rangeCommits(ctx, repo, remoteRef, localRef, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	behind, err := b.rangeCommits(ctx, repo, localRef, remoteRef, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	return len(ahead), len(behind), nil
+}
+
+// Log implements Backend
+func (b *GoGitBackend) Log(ctx context.Context, repoDir, targetRef, sourceRef string, limit int) ([]Commit, error) {
+	repo, err := b.open(repoDir)
+	if err != nil {
+		return nil, err
+	}
+	found, err := b.rangeCommits(ctx, repo, targetRef, sourceRef, limit)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(found) > limit {
+		found = found[:limit]
+	}
+
+	abbrev := b.abbrevLength(ctx, repoDir, repo)
+	commits := make([]Commit, 0, len(found))
+	for _, c := range found {
+		commits = append(commits, toCommit(c, abbrev))
+	}
+	return commits, nil
+}
+
+// FirstParentHashes implements Backend
+func (b *GoGitBackend) FirstParentHashes(ctx context.Context, repoDir, targetRef, sourceRef string) ([]string, error) {
+	repo, err := b.open(repoDir)
+	if err != nil {
+		return nil, err
+	}
+	found, err := b.rangeCommits(ctx, repo, targetRef, sourceRef, 0)
+	if err != nil || len(found) == 0 {
+		return nil, err
+	}
+
+	inRange := make(map[plumbing.Hash]*object.Commit, len(found))
+	for _, c := range found {
+		inRange[c.Hash] = c
+	}
+
+	abbrev := b.abbrevLength(ctx, repoDir, repo)
+	var hashes []string
+	for c := inRange[found[0].Hash]; c != nil; {
+		hashes = append(hashes, abbreviate(c.Hash, abbrev))
+		if len(c.ParentHashes) == 0 {
+			break
+		}
+		c = inRange[c.ParentHashes[0]]
+	}
+	return hashes, nil
+}
+
+// MergedCommits implements Backend
+func (b *GoGitBackend) MergedCommits(ctx context.Context, repoDir, mergeHash string) ([]string, error) {
+	repo, err := b.open(repoDir)
+	if err != nil {
+		return nil, err
+	}
+	found, err := b.rangeCommits(ctx, repo, mergeHash+"^1", mergeHash, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	abbrev := b.abbrevLength(ctx, repoDir, repo)
+	var hashes []string
+	for _, c := range found {
+		if len(c.ParentHashes) <= 1 {
+			hashes = append(hashes, abbreviate(c.Hash, abbrev))
+		}
+	}
+	return hashes, nil
+}
+
+// Walk flags
+const (
+	walkSeen = 1 << iota
+	walkUninteresting
+	walkExpanded
+)
+
+// rangeCommits returns the commits reachable from sourceRef but not from
+// targetRef, newest first, like git log ^targetRef sourceRef. Both sides are
+// walked together by commit date, and the walk stops once only commits
+// reachable from targetRef are left, none as new as a commit found so far.
+// With a limit it also stops once the newest limit commits are settled; more
+// may be returned.
+func (b *GoGitBackend) rangeCommits(ctx context.Context, repo *gogit.Repository, targetRef, sourceRef string, limit int) ([]*object.Commit, error) {
+	source, err := b.resolve(repo, sourceRef)
+	if err != nil {
+		return nil, err
+	}
+	target, err := b.resolve(repo, targetRef)
+	if err != nil {
+		return nil, err
+	}
+
+	flags := make(map[plumbing.Hash]int)
+	queue := &commitQueue{}
+	push := func(c *object.Commit) {
+		queue.seq++
+		heap.Push(queue, queuedCommit{commit: c, seq: queue.seq})
+	}
+
+	flags[target.Hash] = walkSeen | walkUninteresting
+	push(target)
+	if flags[source.Hash] == 0 {
+		flags[source.Hash] = walkSeen
+		push(source)
+	}
+
+	var found []*object.Commit
+	var oldest time.Time
+	for queue.Len() > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		// A queued commit as new as a found one may still be its descendant
+		// (commits made within the same second), so keep walking until it's out
+		if queue.onlyUninteresting(flags) && (len(found) == 0 || queue.items[0].commit.Committer.When.Before(oldest)) {
+			break
+		}
+
+		c := heap.Pop(queue).(queuedCommit).commit
+		if flags[c.Hash]&walkExpanded != 0 {
+			continue
+		}
+		flags[c.Hash] |= walkExpanded
+		uninteresting := flags[c.Hash]&walkUninteresting != 0
+		if !uninteresting {
+			found = append(found, c)
+			if when := c.Committer.When; len(found) == 1 || when.Before(oldest) {
+				oldest = when
+			}
+		}
+
+		for _, parentHash := range c.ParentHashes {
+			f := flags[parentHash]
+			switch {
+			case uninteresting && f&walkUninteresting == 0:
+				flags[parentHash] = f | walkSeen | walkUninteresting
+				if f&walkExpanded != 0 {
+					// Already walked as interesting: walk it again to mark its ancestors
+					flags[parentHash] &^= walkExpanded
+				} else if f&walkSeen != 0 {
+					continue // Still queued, now marked uninteresting
+				}
+			case f&walkSeen != 0:
+				continue
+			default:
+				flags[parentHash] = f | walkSeen
+			}
+
+			parent, err := repo.CommitObject(parentHash)
+			if err != nil {
+				return nil, err
+			}
+			push(parent)
+		}
+
+		if limit > 0 && settled(found, limit, flags, queue) {
+			break
+		}
+	}
+
+	// Commits walked before a descendant of targetRef reached them are dropped here
+	result := found[:0]
+	for _, c := range found {
+		if flags[c.Hash]&walkUninteresting == 0 {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+type queuedCommit struct {
+	commit *object.Commit
+	seq    int
+}
+
+// commitQueue pops the newest commit first; commits with the same date come
+// out in the order they were queued, as in git
+type commitQueue struct {
+	items []queuedCommit
+	seq   int
+}
+
+func (q *commitQueue) Len() int { return len(q.items) }
+func (q *commitQueue) Less(i, j int) bool {
+	ti, tj := q.items[i].commit.Committer.When, q.items[j].commit.Committer.When
+	if !ti.Equal(tj) {
+		return ti.After(tj)
+	}
+	return q.items[i].seq < q.items[j].seq
+}
+func (q *commitQueue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+func (q *commitQueue) Push(x any)    { q.items = append(q.items, x.(queuedCommit)) }
+func (q *commitQueue) Pop() any {
+	last := q.items[len(q.items)-1]
+	q.items = q.items[:len(q.items)-1]
+	return last
+}
+
+// onlyUninteresting reports whether every queued commit is reachable from the target
+func (q *commitQueue) onlyUninteresting(flags map[plumbing.Hash]int) bool {
+	for _, item := range q.items {
+		if flags[item.commit.Hash]&walkUninteresting == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// newestUninteresting returns the date of the newest queued commit reachable from the target
+func (q *commitQueue) newestUninteresting(flags map[plumbing.Hash]int) (time.Time, bool) {
+	var newest time.Time
+	ok := false
+	for _, item := range q.items {
+		if flags[item.commit.Hash]&walkUninteresting == 0 {
+			continue
+		}
+		if when := item.commit.Committer.When; !ok || when.After(newest) {
+			newest, ok = when, true
+		}
+	}
+	return newest, ok
+}
+
+// settled reports whether the first limit commits still in range can no longer
+// be dropped: only a queued commit reachable from the target and at least as new
+// as them could turn out to be their descendant
+func settled(found []*object.Commit, limit int, flags map[plumbing.Hash]int, queue *commitQueue) bool {
+	if len(found) < limit {
+		return false
+	}
+	var last *object.Commit
+	count := 0
+	for _, c := range found {
+		if flags[c.Hash]&walkUninteresting == 0 {
+			last = c
+			if count++; count == limit {
+				break
+			}
+		}
+	}
+	if count < limit {
+		return false
+	}
+	newest, ok := queue.newestUninteresting(flags)
+	return !ok || newest.Before(last.Committer.When)
+}
+
+// toCommit converts a go-git commit the way logFormat renders it
+func toCommit(c *object.Commit, abbrev int) Commit {
+	subject, body := splitMessage(c.Message)
+	parents := make([]string, 0, len(c.ParentHashes))
+	for _, parent := range c.ParentHashes {
+		parents = append(parents, abbreviate(parent, abbrev))
+	}
+	return Commit{
+		Hash:    abbreviate(c.Hash, abbrev),
+		Author:  c.Author.Name,
+		Message: subject,
+		Date:    c.Author.When.Format("2006-01-02"),
+		Parents: parents,
+		Body:    body,
+	}
+}
+
+// splitMessage splits a commit message into its subject (the first paragraph,
+// joined into one line like git's %s) and body
+func splitMessage(message string) (string, string) {
+	message = strings.TrimLeft(message, "\n")
+	paragraph, body, _ := strings.Cut(message, "\n\n")
+
+	lines := strings.Split(strings.TrimSpace(paragraph), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.Join(lines, " "), strings.TrimSpace(body)
+}
+
+// abbreviate shortens a hash to length hex digits, kept within git's 4 to 40
+func abbreviate(hash plumbing.Hash, length int) string {
+	return hash.String()[:min(max(length, 4), 40)]
+}
@@ -0,0 +1,239 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// setupBackendRepo builds a repository with a merge, a multi-paragraph message
+// and a diverged target for comparing backends
+func setupBackendRepo(t testing.TB) string {
+	repoDir := t.TempDir()
+	runGit(t, repoDir, "init", "--quiet", "--initial-branch=main")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+
+	commit := func(message string) {
+		runGit(t, repoDir, "commit", "--quiet", "--allow-empty", "-m", message)
+	}
+
+	commit("base commit")
+	runGit(t, repoDir, "branch", "ZUP-1-hml")
+	runGit(t, repoDir, "checkout", "--quiet", "-b", "ZUP-1-prd")
+	commit("feat: direct commit\nwrapped subject\n\nBody paragraph.\n\nRefs: ZUP-1")
+	runGit(t, repoDir, "checkout", "--quiet", "-b", "feature")
+	commit("fix: feature commit")
+	runGit(t, repoDir, "checkout", "--quiet", "ZUP-1-prd")
+	commit("chore: after branching")
+	runGit(t, repoDir, "merge", "--quiet", "--no-ff", "-m", "Merge feature", "feature")
+	runGit(t, repoDir, "checkout", "--quiet", "ZUP-1-hml")
+	commit("feat: only on hml")
+	runGit(t, repoDir, "checkout", "--quiet", "ZUP-1-prd")
+	return repoDir
+}
+
+func TestGoGitBackend_MatchesCLI(t *testing.T) {
+	repoDir := setupBackendRepo(t)
+	cli, goGit := CLIBackend{}, NewGoGitBackend()
+
+	compare := func(name string, want, got interface{}, wantErr, gotErr error) {
+		t.Helper()
+		if (wantErr == nil) != (gotErr == nil) {
+			t.Errorf("%s: git error %v, go-git error %v", name, wantErr, gotErr)
+			return
+		}
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("%s: git returned %v, go-git returned %v", name, want, got)
+		}
+	}
+
+	wantBranch, wantErr := cli.CurrentBranch(context.Background(), repoDir)
+	gotBranch, gotErr := goGit.CurrentBranch(context.Background(), repoDir)
+	compare("CurrentBranch", wantBranch, gotBranch, wantErr, gotErr)
+
+	wantUser, wantErr := cli.CurrentUser(context.Background(), repoDir)
+	gotUser, gotErr := goGit.CurrentUser(context.Background(), repoDir)
+	compare("CurrentUser", wantUser, gotUser, wantErr, gotErr)
+
+	for _, ref := range []string{"ZUP-1-prd", "ZUP-1-hml", "HEAD", "missing"} {
+		want, wantErr := cli.ResolveCommit(context.Background(), repoDir, ref)
+		got, gotErr := goGit.ResolveCommit(context.Background(), repoDir, ref)
+		compare("ResolveCommit "+ref, want, got, wantErr, gotErr)
+	}
+
+	wantAhead, wantBehind, wantErr := cli.AheadBehind(context.Background(), repoDir, "ZUP-1-prd", "ZUP-1-hml")
+	gotAhead, gotBehind, gotErr := goGit.AheadBehind(context.Background(), repoDir, "ZUP-1-prd", "ZUP-1-hml")
+	compare("AheadBehind", []int{wantAhead, wantBehind}, []int{gotAhead, gotBehind}, wantErr, gotErr)
+
+	for _, limit := range []int{0, 1, 2} {
+		want, wantErr := cli.Log(context.Background(), repoDir, "ZUP-1-hml", "ZUP-1-prd", limit)
+		got, gotErr := goGit.Log(context.Background(), repoDir, "ZUP-1-hml", "ZUP-1-prd", limit)
+		compare(fmt.Sprintf("Log limit %d", limit), want, got, wantErr, gotErr)
+	}
+
+	wantFirst, wantErr := cli.FirstParentHashes(context.Background(), repoDir, "ZUP-1-hml", "ZUP-1-prd")
+	gotFirst, gotErr := goGit.FirstParentHashes(context.Background(), repoDir, "ZUP-1-hml", "ZUP-1-prd")
+	sort.Strings(wantFirst)
+	sort.Strings(gotFirst)
+	compare("FirstParentHashes", wantFirst, gotFirst, wantErr, gotErr)
+
+	merge, _ := cli.ResolveCommit(context.Background(), repoDir, "ZUP-1-prd")
+	wantMerged, wantErr := cli.MergedCommits(context.Background(), repoDir, merge)
+	gotMerged, gotErr := goGit.MergedCommits(context.Background(), repoDir, merge)
+	compare("MergedCommits", wantMerged, gotMerged, wantErr, gotErr)
+}
+
+func TestGoGitBackend_LogLimit(t *testing.T) {
+	repoDir := t.TempDir()
+	runGit(t, repoDir, "init", "--quiet", "--initial-branch=main")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+
+	// Distinct dates let the go-git walk stop before reaching the merge base
+	day := 0
+	commit := func(message string) {
+		day++
+		t.Setenv("GIT_COMMITTER_DATE", fmt.Sprintf("2024-01-%02dT12:00:00Z", day))
+		runGit(t, repoDir, "commit", "--quiet", "--allow-empty", "-m", message)
+	}
+
+	commit("base commit")
+	runGit(t, repoDir, "branch", "ZUP-1-hml")
+	runGit(t, repoDir, "checkout", "--quiet", "-b", "ZUP-1-prd")
+	for i := 1; i <= 5; i++ {
+		commit(fmt.Sprintf("feat: prd commit %d", i))
+	}
+	runGit(t, repoDir, "checkout", "--quiet", "ZUP-1-hml")
+	commit("feat: only on hml")
+	runGit(t, repoDir, "checkout", "--quiet", "ZUP-1-prd")
+	for i := 6; i <= 10; i++ {
+		commit(fmt.Sprintf("feat: prd commit %d", i))
+	}
+
+	cli, goGit := CLIBackend{}, NewGoGitBackend()
+	for _, limit := range []int{0, 1, 3, 10, 20} {
+		want, err := cli.Log(context.Background(), repoDir, "ZUP-1-hml", "ZUP-1-prd", limit)
+		if err != nil {
+			t.Fatalf("git log failed: %v", err)
+		}
+		got, err := goGit.Log(context.Background(), repoDir, "ZUP-1-hml", "ZUP-1-prd", limit)
+		if err != nil {
+			t.Fatalf("go-git log failed: %v", err)
+		}
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("Log limit %d: git returned %v, go-git returned %v", limit, want, got)
+		}
+	}
+}
+
+func TestAbbreviate(t *testing.T) {
+	hash := plumbing.NewHash("1234567890abcdef1234567890abcdef12345678")
+	for _, tt := range []struct {
+		length int
+		want   string
+	}{
+		{7, "1234567"},
+		{0, "1234"},
+		{64, hash.String()},
+	} {
+		if got := abbreviate(hash, tt.length); got != tt.want {
+			t.Errorf("abbreviate(%d) = %q, want %q", tt.length, got, tt.want)
+		}
+	}
+}
+
+func TestSplitMessage(t *testing.T) {
+	tests := []struct {
+		message, subject, body string
+	}{
+		{"fix: typo\n", "fix: typo", ""},
+		{"feat: login\n\nAdds the form.\n\nRefs: ZUP-1\n", "feat: login", "Adds the form.\n\nRefs: ZUP-1"},
+		{"\nwrapped\nsubject\n\nbody", "wrapped subject", "body"},
+	}
+
+	for _, tt := range tests {
+		subject, body := splitMessage(tt.message)
+		if subject != tt.subject || body != tt.body {
+			t.Errorf("splitMessage(%q) = %q, %q; want %q, %q", tt.message, subject, body, tt.subject, tt.body)
+		}
+	}
+}
+
+// setupBenchmarkRepo builds a repository where the PRD branch has n commits the
+// HML branch lacks, on top of n shared ones, using git fast-import
+func setupBenchmarkRepo(b *testing.B, n int) string {
+	repoDir := setupBackendRepo(b)
+
+	var stream strings.Builder
+	for i := 0; i < 2*n; i++ {
+		branch := "refs/heads/ZUP-2-prd"
+		if i < n {
+			branch = "refs/heads/ZUP-2-hml"
+		}
+		message := fmt.Sprintf("feat: change %d", i)
+		fmt.Fprintf(&stream, "commit %s\nmark :%d\ncommitter Test User <test@example.com> %d +0000\ndata %d\n%s\n",
+			branch, i+1, 1700000000+i, len(message), message)
+		if i > 0 {
+			fmt.Fprintf(&stream, "from :%d\n", i)
+		}
+		fmt.Fprintf(&stream, "M 644 inline file.txt\ndata %d\n%d\n\n", len(fmt.Sprint(i))+1, i)
+	}
+
+	cmd := exec.Command("git", "fast-import", "--quiet")
+	cmd.Dir = repoDir
+	cmd.Stdin = strings.NewReader(stream.String())
+	if output, err := cmd.CombinedOutput(); err != nil {
+		b.Fatalf("fast-import failed: %v: %s", err, output)
+	}
+	return repoDir
+}
+
+func BenchmarkBackends(b *testing.B) {
+	repoDir := setupBenchmarkRepo(b, 1000)
+	backends := []struct {
+		name    string
+		backend Backend
+	}{
+		{BackendGit, CLIBackend{}},
+		{BackendGoGit, NewGoGitBackend()},
+	}
+
+	for _, bb := range backends {
+		b.Run(bb.name+"/ResolveCommit", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := bb.backend.ResolveCommit(context.Background(), repoDir, "ZUP-2-prd"); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(bb.name+"/CurrentUser", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := bb.backend.CurrentUser(context.Background(), repoDir); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(bb.name+"/Log", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				commits, err := bb.backend.Log(context.Background(), repoDir, "ZUP-2-hml", "ZUP-2-prd", 0)
+				if err != nil || len(commits) != 1000 {
+					b.Fatalf("Log returned %d commits: %v", len(commits), err)
+				}
+			}
+		})
+		b.Run(bb.name+"/Log100", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := bb.backend.Log(context.Background(), repoDir, "main", "ZUP-2-hml", 100); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
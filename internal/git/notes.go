@@ -0,0 +1,95 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ListNotes returns the notes under a notes ref (e.g. refs/notes/chr-marks) by
+// the full hash of the commit each one annotates. A ref that doesn't exist yet
+// has no notes.
+func ListNotes(ctx context.Context, repoDir, ref string) (map[string]string, error) {
+	output, err := run(ctx, repoDir, "notes", "--ref", ref, "list")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notes in %s: %w", ref, err)
+	}
+
+	// Each line is "<note blob> <annotated commit>". Notes with the same text
+	// share a blob, so one blob can annotate several commits.
+	var blobs []string
+	commitsOf := make(map[string][]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 {
+			if _, ok := commitsOf[fields[0]]; !ok {
+				blobs = append(blobs, fields[0])
+			}
+			commitsOf[fields[0]] = append(commitsOf[fields[0]], fields[1])
+		}
+	}
+	notes := make(map[string]string, len(commitsOf))
+	if len(blobs) == 0 {
+		return notes, nil
+	}
+
+	// One git process reads every note
+	contents, err := RunnerFrom(ctx).Run(ctx, Command{
+		Dir:   repoDir,
+		Args:  []string{"cat-file", "--batch"},
+		Stdin: []byte(strings.Join(blobs, "\n") + "\n"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notes in %s: %w", ref, err)
+	}
+	blobNotes, err := parseCatFileBatch(contents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notes in %s: %w", ref, err)
+	}
+	for blob, note := range blobNotes {
+		for _, commit := range commitsOf[blob] {
+			notes[commit] = note
+		}
+	}
+	return notes, nil
+}
+
+// parseCatFileBatch splits git cat-file --batch output, a "<hash> <type> <size>"
+// header followed by the object and a newline per object, into contents by hash
+func parseCatFileBatch(output []byte) (map[string]string, error) {
+	objects := make(map[string]string)
+	for len(output) > 0 {
+		header, rest, ok := bytes.Cut(output, []byte("\n"))
+		if !ok {
+			return nil, fmt.Errorf("truncated cat-file output")
+		}
+		fields := strings.Fields(string(header))
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected cat-file header %q", header)
+		}
+		size, err := strconv.Atoi(fields[2])
+		if err != nil || size+1 > len(rest) {
+			return nil, fmt.Errorf("unexpected cat-file header %q", header)
+		}
+		objects[fields[0]] = string(rest[:size])
+		output = rest[size+1:]
+	}
+	return objects, nil
+}
+
+// AddNote sets the note on a commit under a notes ref, replacing any note it had
+func AddNote(ctx context.Context, repoDir, ref, commit, note string) error {
+	if _, err := run(ctx, repoDir, "notes", "--ref", ref, "add", "--force", "--message", note, commit); err != nil {
+		return fmt.Errorf("failed to add note to %s: %w", commit, err)
+	}
+	return nil
+}
+
+// RemoveNote deletes the note on a commit under a notes ref, if it has one
+func RemoveNote(ctx context.Context, repoDir, ref, commit string) error {
+	if _, err := run(ctx, repoDir, "notes", "--ref", ref, "remove", "--ignore-missing", commit); err != nil {
+		return fmt.Errorf("failed to remove note from %s: %w", commit, err)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout bounds a single git command run by the default runner
+const DefaultTimeout = 10 * time.Minute
+
+// Command is a git invocation
+type Command struct {
+	Dir  string
+	Args []string // Arguments after "git"
+	Env  []string // Extra KEY=VALUE entries added to the environment
+	// Stdin is fed to git's standard input when set
+	Stdin []byte
+
+	// Detached commands run to completion even once ctx is cancelled, and out of
+	// reach of the terminal's Ctrl-C, so they never leave the repository half-updated
+	Detached bool
+}
+
+// String renders the command the way it would be typed in a shell
+func (c Command) String() string {
+	parts := []string{"git"}
+	for _, arg := range c.Args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'$*?[]{}()|&;<>\\`") {
+			arg = fmt.Sprintf("%q", arg)
+		}
+		parts = append(parts, arg)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Runner runs git commands and returns their stdout. A command that fails
+// returns a *CommandError carrying its stderr.
+type Runner interface {
+	Run(ctx context.Context, cmd Command) ([]byte, error)
+}
+
+// CommandError reports a git command that failed
+type CommandError struct {
+	Command  Command
+	Stderr   string
+	ExitCode int // -1 when git could not be started or was killed
+	Err      error
+}
+
+func (e *CommandError) Error() string {
+	if stderr := strings.TrimSpace(e.Stderr); stderr != "" {
+		return stderr
+	}
+	return fmt.Sprintf("%s: %v", e.Command, e.Err)
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// ExecRunner runs git as a child process
+type ExecRunner struct {
+	Timeout time.Duration // Per-command timeout, 0 for none
+}
+
+// Run implements Runner
+func (r *ExecRunner) Run(ctx context.Context, c Command) ([]byte, error) {
+	if c.Detached {
+		ctx = context.WithoutCancel(ctx)
+	}
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", c.Args...)
+	cmd.Dir = c.Dir
+	if c.Detached {
+		detach(cmd)
+	}
+	if len(c.Env) > 0 {
+		cmd.Env = append(os.Environ(), c.Env...)
+	}
+	if c.Stdin != nil {
+		cmd.Stdin = bytes.NewReader(c.Stdin)
+	}
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		cmdErr := &CommandError{Command: c, Stderr: stderr.String(), ExitCode: -1, Err: err}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			cmdErr.ExitCode = exitErr.ExitCode()
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			cmdErr.Err = ctxErr
+		}
+		return stdout.Bytes(), cmdErr
+	}
+	return stdout.Bytes(), nil
+}
+
+// TraceRunner prints each command to Out before handing it to Next, like "+ git status"
+type TraceRunner struct {
+	Next Runner
+	Out  io.Writer
+}
+
+// Run implements Runner
+func (r *TraceRunner) Run(ctx context.Context, c Command) ([]byte, error) {
+	fmt.Fprintf(r.Out, "+ %s\n", c)
+	return r.Next.Run(ctx, c)
+}
+
+// defaultRunner runs commands when the context carries no runner
+var defaultRunner Runner = &ExecRunner{Timeout: DefaultTimeout}
+
+type runnerKey struct{}
+
+// WithRunner returns a context whose git commands run through r
+func WithRunner(ctx context.Context, r Runner) context.Context {
+	return context.WithValue(ctx, runnerKey{}, r)
+}
+
+// RunnerFrom returns the runner carried by ctx, or the default one
+func RunnerFrom(ctx context.Context) Runner {
+	if r, ok := ctx.Value(runnerKey{}).(Runner); ok {
+		return r
+	}
+	return defaultRunner
+}
+
+// run runs git in repoDir and returns its stdout
+func run(ctx context.Context, repoDir string, args ...string) ([]byte, error) {
+	return RunnerFrom(ctx).Run(ctx, Command{Dir: repoDir, Args: args})
+}
+
+// runDetached runs a git command that updates the working tree, which is never
+// cut short by ctx
+func runDetached(ctx context.Context, repoDir string, args ...string) ([]byte, error) {
+	return RunnerFrom(ctx).Run(ctx, Command{Dir: repoDir, Args: args, Detached: true})
+}
@@ -0,0 +1,137 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// fakeRunner answers git commands from canned responses keyed by their arguments
+// and records every command it was asked to run
+type fakeRunner struct {
+	responses map[string]fakeResponse
+	commands  []Command
+}
+
+type fakeResponse struct {
+	stdout string
+	stderr string // A non-empty stderr makes the command fail
+}
+
+func (f *fakeRunner) Run(_ context.Context, c Command) ([]byte, error) {
+	f.commands = append(f.commands, c)
+	response, ok := f.responses[strings.Join(c.Args, " ")]
+	if !ok {
+		return nil, &CommandError{Command: c, Stderr: "fatal: unexpected command", ExitCode: 128, Err: errors.New("exit status 128")}
+	}
+	if response.stderr != "" {
+		return nil, &CommandError{Command: c, Stderr: response.stderr, ExitCode: 1, Err: errors.New("exit status 1")}
+	}
+	return []byte(response.stdout), nil
+}
+
+func TestCommand_String(t *testing.T) {
+	c := Command{Args: []string{"log", "--format=%h %s", "^main", "ZUP-1-prd"}}
+	if got, want := c.String(), `git log "--format=%h %s" ^main ZUP-1-prd`; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestExecRunner_CapturesStderr(t *testing.T) {
+	runner := &ExecRunner{}
+	_, err := runner.Run(context.Background(), Command{Dir: t.TempDir(), Args: []string{"rev-parse", "--verify", "missing"}})
+
+	var cmdErr *CommandError
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("Expected a CommandError, got %v", err)
+	}
+	if cmdErr.ExitCode == 0 || !strings.Contains(cmdErr.Stderr, "fatal") {
+		t.Errorf("Expected a non-zero exit code and stderr, got %d %q", cmdErr.ExitCode, cmdErr.Stderr)
+	}
+}
+
+func TestExecRunner_Env(t *testing.T) {
+	runner := &ExecRunner{}
+	output, err := runner.Run(context.Background(), Command{
+		Dir:  t.TempDir(),
+		Args: []string{"var", "GIT_AUTHOR_IDENT"},
+		Env:  []string{"GIT_AUTHOR_NAME=Env User", "GIT_AUTHOR_EMAIL=env@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !strings.HasPrefix(string(output), "Env User <env@example.com>") {
+		t.Errorf("Expected the env to reach git, got %q", output)
+	}
+}
+
+func TestExecRunner_Detached(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	runner := &ExecRunner{}
+	if _, err := runner.Run(ctx, Command{Dir: t.TempDir(), Args: []string{"version"}}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled context to stop the command, got %v", err)
+	}
+	output, err := runner.Run(ctx, Command{Dir: t.TempDir(), Args: []string{"version"}, Detached: true})
+	if err != nil || !strings.HasPrefix(string(output), "git version") {
+		t.Errorf("Expected a detached command to run anyway, got %q, %v", output, err)
+	}
+}
+
+func TestTraceRunner(t *testing.T) {
+	fake := &fakeRunner{responses: map[string]fakeResponse{"branch --show-current": {stdout: "ZUP-1-prd\n"}}}
+	var trace bytes.Buffer
+	ctx := WithRunner(context.Background(), &TraceRunner{Next: fake, Out: &trace})
+
+	branch, err := GetCurrentBranch(ctx, "/repo")
+	if err != nil || branch != "ZUP-1-prd" {
+		t.Fatalf("GetCurrentBranch() = %q, %v", branch, err)
+	}
+	if trace.String() != "+ git branch --show-current\n" {
+		t.Errorf("Unexpected trace %q", trace.String())
+	}
+	if len(fake.commands) != 1 || fake.commands[0].Dir != "/repo" {
+		t.Errorf("Expected one command in /repo, got %+v", fake.commands)
+	}
+}
+
+func TestPushBranch_RejectedWithFakeRunner(t *testing.T) {
+	fake := &fakeRunner{responses: map[string]fakeResponse{
+		"rev-parse --verify --quiet ZUP-1-hml^{commit}":              {stdout: "abc123\n"},
+		"push origin refs/heads/ZUP-1-hml:refs/heads/ZUP-1-hml":      {stderr: "! [rejected] ZUP-1-hml -> ZUP-1-hml (fetch first)"},
+		"fetch origin ZUP-1-hml":                                     {},
+		"rev-list --left-right --count ZUP-1-hml...origin/ZUP-1-hml": {stdout: "2\t3\n"},
+	}}
+	ctx := WithRunner(context.Background(), fake)
+
+	_, err := PushBranch(ctx, "/repo", "origin", "ZUP-1-hml", false)
+	if err == nil || !strings.Contains(err.Error(), "2 ahead and 3 behind origin/ZUP-1-hml") {
+		t.Errorf("Expected the rejection to report ahead/behind counts, got %v", err)
+	}
+}
+
+// branchBackend answers CurrentBranch with a fixed name
+type branchBackend struct {
+	CLIBackend
+	branch string
+}
+
+func (b branchBackend) CurrentBranch(ctx context.Context, repoDir string) (string, error) {
+	return b.branch, nil
+}
+
+func TestWithBackend(t *testing.T) {
+	fake := &fakeRunner{responses: map[string]fakeResponse{"branch --show-current": {stdout: "ZUP-1-prd\n"}}}
+	ctx := WithRunner(context.Background(), fake)
+
+	branch, err := GetCurrentBranch(WithBackend(ctx, branchBackend{branch: "ZUP-1-hml"}), "/repo")
+	if err != nil || branch != "ZUP-1-hml" || len(fake.commands) != 0 {
+		t.Errorf("Expected the context backend to answer without git, got %q, %v, %+v", branch, err, fake.commands)
+	}
+	if branch, err := GetCurrentBranch(ctx, "/repo"); err != nil || branch != "ZUP-1-prd" {
+		t.Errorf("Expected the git CLI backend by default, got %q, %v", branch, err)
+	}
+}
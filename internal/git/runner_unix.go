@@ -0,0 +1,14 @@
+//go:build unix
+
+package git
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detach starts the command in its own process group, so the SIGINT a terminal
+// sends to the foreground group on Ctrl-C reaches chr but not git
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
@@ -0,0 +1,48 @@
+package httpjson
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" || r.Header.Get("Accept") != "application/vnd.test+json" {
+			t.Errorf("Unexpected headers: %v", r.Header)
+		}
+		var got map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		if got["name"] != "chr" {
+			t.Errorf("Unexpected payload: %v", got)
+		}
+		w.Write([]byte(`{"id": 7}`))
+	}))
+	defer server.Close()
+
+	var out struct {
+		ID int `json:"id"`
+	}
+	headers := map[string]string{"Accept": "application/vnd.test+json"}
+	if err := Do(server.Client(), http.MethodPost, server.URL, headers, map[string]string{"name": "chr"}, &out); err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	if out.ID != 7 {
+		t.Errorf("Expected id 7, got %d", out.ID)
+	}
+}
+
+func TestDo_StatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no such issue", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	err := Do(server.Client(), http.MethodGet, server.URL, nil, nil, nil)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound || statusErr.Body != "no such issue" {
+		t.Errorf("Expected a 404 StatusError, got %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package jira
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/carlosarraes/chr/internal/httpjson"
+)
+
+const requestTimeout = 30 * time.Second
+
+// Client talks to the Jira REST API (v2, which takes plain-text comment bodies)
+type Client struct {
+	BaseURL string
+	User    string
+	Token   string
+	HTTP    *http.Client
+}
+
+// NewClient builds a Jira client. With a user the token is sent as basic auth
+// (Jira Cloud API tokens); without one it is sent as a bearer token (Data Center PATs).
+func NewClient(baseURL, user, token string) (*Client, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("jira_url is not set")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("jira_token is not set (or export CHR_JIRA_TOKEN)")
+	}
+
+	return &Client{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		User:    user,
+		Token:   token,
+		HTTP:    &http.Client{Timeout: requestTimeout},
+	}, nil
+}
+
+// AddComment posts a comment to an issue
+func (c *Client) AddComment(issueKey, body string) error {
+	payload := map[string]string{"body": body}
+	if err := c.do(http.MethodPost, c.issueURL(issueKey)+"/comment", payload, nil); err != nil {
+		return fmt.Errorf("failed to comment on %s: %w", issueKey, err)
+	}
+	return nil
+}
+
+// TransitionTo moves an issue to the given status, matching either the transition
+// name or its target status name, case-insensitively
+func (c *Client) TransitionTo(issueKey, status string) error {
+	var available struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			To   struct {
+				Name string `json:"name"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	if err := c.do(http.MethodGet, c.issueURL(issueKey)+"/transitions", nil, &available); err != nil {
+		return fmt.Errorf("failed to list transitions of %s: %w", issueKey, err)
+	}
+
+	for _, transition := range available.Transitions {
+		if strings.EqualFold(transition.Name, status) || strings.EqualFold(transition.To.Name, status) {
+			payload := map[string]interface{}{"transition": map[string]string{"id": transition.ID}}
+			if err := c.do(http.MethodPost, c.issueURL(issueKey)+"/transitions", payload, nil); err != nil {
+				return fmt.Errorf("failed to move %s to %s: %w", issueKey, status, err)
+			}
+			return nil
+		}
+	}
+
+	return fmt.Errorf("no transition to '%s' available for %s", status, issueKey)
+}
+
+func (c *Client) issueURL(issueKey string) string {
+	return c.BaseURL + "/rest/api/2/issue/" + url.PathEscape(issueKey)
+}
+
+// do sends a request with an optional JSON payload and decodes the response into out when given
+func (c *Client) do(method, endpoint string, payload, out interface{}) error {
+	auth := "Bearer " + c.Token
+	if c.User != "" {
+		auth = "Basic " + base64.StdEncoding.EncodeToString([]byte(c.User+":"+c.Token))
+	}
+	return httpjson.Do(c.HTTP, method, endpoint, map[string]string{"Authorization": auth}, payload, out)
+}
@@ -0,0 +1,89 @@
+package jira
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAddComment(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/rest/api/2/issue/ZUP-123/comment" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		user, token, ok := r.BasicAuth()
+		if !ok || user != "me@example.com" || token != "secret" {
+			t.Errorf("Unexpected basic auth %q %q", user, token)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": "10000"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "me@example.com", "secret")
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	if err := client.AddComment("ZUP-123", "picked"); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	if got["body"] != "picked" {
+		t.Errorf("Unexpected payload: %+v", got)
+	}
+}
+
+func TestTransitionTo(t *testing.T) {
+	var moved string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer secret" {
+			t.Errorf("Unexpected Authorization header %q", auth)
+		}
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`{"transitions": [
+				{"id": "11", "name": "Start", "to": {"name": "In Progress"}},
+				{"id": "31", "name": "Send to QA", "to": {"name": "In Homologation"}}
+			]}`))
+		case http.MethodPost:
+			var payload struct {
+				Transition struct {
+					ID string `json:"id"`
+				} `json:"transition"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+			moved = payload.Transition.ID
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "", "secret")
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	if err := client.TransitionTo("ZUP-123", "in homologation"); err != nil {
+		t.Fatalf("TransitionTo failed: %v", err)
+	}
+	if moved != "31" {
+		t.Errorf("Expected transition 31, got %q", moved)
+	}
+
+	err = client.TransitionTo("ZUP-123", "Done")
+	if err == nil || !strings.Contains(err.Error(), "no transition to 'Done'") {
+		t.Errorf("Expected missing transition error, got %v", err)
+	}
+}
+
+func TestNewClient_Errors(t *testing.T) {
+	if _, err := NewClient("", "", "secret"); err == nil {
+		t.Error("Expected error for missing URL")
+	}
+	if _, err := NewClient("http://jira", "", ""); err == nil {
+		t.Error("Expected error for missing token")
+	}
+}
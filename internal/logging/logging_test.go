@@ -0,0 +1,66 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLevelFromFlags(t *testing.T) {
+	tests := []struct {
+		name                  string
+		quiet, verbose, debug bool
+		want                  slog.Level
+	}{
+		{"default", false, false, false, slog.LevelWarn},
+		{"quiet", true, false, false, slog.LevelError},
+		{"verbose", false, true, false, slog.LevelInfo},
+		{"debug", false, false, true, slog.LevelDebug},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LevelFromFlags(tt.quiet, tt.verbose, tt.debug); got != tt.want {
+				t.Errorf("LevelFromFlags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsoleHandler(t *testing.T) {
+	var console bytes.Buffer
+	log := New(Options{Level: slog.LevelInfo, Console: &console})
+
+	log.Debug("hidden")
+	log.Info("fetching branches", "remote", "origin")
+	log.With("issue", "ZUP-1").Warn("Jira comment failed", "err", "401 Unauthorized")
+	log.WithGroup("match").Error("failed", "score", 80)
+
+	want := "fetching branches remote=origin\n" +
+		"Warning: Jira comment failed issue=ZUP-1 err=\"401 Unauthorized\"\n" +
+		"Error: failed match.score=80\n"
+	if console.String() != want {
+		t.Errorf("Unexpected console output:\n%s\nwant:\n%s", console.String(), want)
+	}
+}
+
+func TestNew_LogFile(t *testing.T) {
+	var console, file bytes.Buffer
+	log := New(Options{Level: slog.LevelError, Console: &console, File: &file})
+
+	log.Debug("listing commits", "source", "ZUP-1-prd")
+
+	if console.Len() != 0 {
+		t.Errorf("Expected nothing on the console, got %q", console.String())
+	}
+
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(file.String())), &record); err != nil {
+		t.Fatalf("Expected a JSON record, got %q: %v", file.String(), err)
+	}
+	if record["level"] != "DEBUG" || record["msg"] != "listing commits" || record["source"] != "ZUP-1-prd" {
+		t.Errorf("Unexpected record %v", record)
+	}
+}
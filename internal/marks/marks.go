@@ -0,0 +1,144 @@
+package marks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/carlosarraes/chr/internal/git"
+)
+
+// Ref is the notes ref marks are kept in. Like any notes ref it is shared by
+// pushing and fetching it: git push origin refs/notes/chr-marks
+const Ref = "refs/notes/chr-marks"
+
+const (
+	KindIgnored = "ignored" // Never to be picked (chr ignore)
+	KindPicked  = "picked"  // Already picked by other means (chr mark-picked)
+)
+
+// Mark records a decision about a source commit that matching can't make on its own
+type Mark struct {
+	Commit string // Full hash of the marked commit
+	Kind   string
+	Target string // Full hash of the commit a picked commit became, if known
+	User   string
+	Date   time.Time
+}
+
+// String renders the mark as the text of its note
+func (m Mark) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "chr: %s\n", m.Kind)
+	if m.Target != "" {
+		fmt.Fprintf(&b, "target: %s\n", m.Target)
+	}
+	if m.User != "" {
+		fmt.Fprintf(&b, "by: %s\n", m.User)
+	}
+	if !m.Date.IsZero() {
+		fmt.Fprintf(&b, "date: %s\n", m.Date.UTC().Format(time.RFC3339))
+	}
+	return b.String()
+}
+
+// Parse reads the note on a commit back into a mark
+func Parse(commit, note string) (Mark, error) {
+	m := Mark{Commit: commit}
+	for _, line := range strings.Split(note, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "chr":
+			m.Kind = value
+		case "target":
+			m.Target = value
+		case "by":
+			m.User = value
+		case "date":
+			m.Date, _ = time.Parse(time.RFC3339, value)
+		}
+	}
+
+	if m.Kind != KindIgnored && m.Kind != KindPicked {
+		return Mark{}, fmt.Errorf("note on %s is not a chr mark", commit)
+	}
+	return m, nil
+}
+
+// Load reads every mark in the repository, oldest first. Notes that aren't
+// marks are skipped.
+func Load(ctx context.Context, repoDir string) ([]Mark, error) {
+	notes, err := git.ListNotes(ctx, repoDir, Ref)
+	if err != nil {
+		return nil, err
+	}
+
+	marks := make([]Mark, 0, len(notes))
+	for commit, note := range notes {
+		if m, err := Parse(commit, note); err == nil {
+			marks = append(marks, m)
+		}
+	}
+	sort.Slice(marks, func(i, j int) bool {
+		if !marks[i].Date.Equal(marks[j].Date) {
+			return marks[i].Date.Before(marks[j].Date)
+		}
+		return marks[i].Commit < marks[j].Commit
+	})
+	return marks, nil
+}
+
+// Save records a mark, replacing any earlier mark on the same commit
+func Save(ctx context.Context, repoDir string, m Mark) error {
+	return git.AddNote(ctx, repoDir, Ref, m.Commit, m.String())
+}
+
+// Remove forgets the mark on a commit, if it has one
+func Remove(ctx context.Context, repoDir, commit string) error {
+	return git.RemoveNote(ctx, repoDir, Ref, commit)
+}
+
+// Set looks marks up by the full or abbreviated hash of their commit
+type Set struct {
+	hashes []string // Sorted full hashes
+	marks  map[string]Mark
+}
+
+// NewSet indexes marks for lookups
+func NewSet(marks []Mark) *Set {
+	s := &Set{marks: make(map[string]Mark, len(marks))}
+	for _, m := range marks {
+		if _, ok := s.marks[m.Commit]; !ok {
+			s.hashes = append(s.hashes, m.Commit)
+		}
+		s.marks[m.Commit] = m
+	}
+	sort.Strings(s.hashes)
+	return s
+}
+
+// Lookup returns the mark on a commit. A nil Set has no marks.
+func (s *Set) Lookup(hash string) (Mark, bool) {
+	if s == nil || hash == "" {
+		return Mark{}, false
+	}
+	i := sort.SearchStrings(s.hashes, hash)
+	if i < len(s.hashes) && strings.HasPrefix(s.hashes[i], hash) {
+		return s.marks[s.hashes[i]], true
+	}
+	return Mark{}, false
+}
+
+// Len returns how many marks the set holds
+func (s *Set) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.hashes)
+}
@@ -0,0 +1,104 @@
+package marks
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse_RoundTrip(t *testing.T) {
+	m := Mark{
+		Commit: "1234567890abcdef1234567890abcdef12345678",
+		Kind:   KindPicked,
+		Target: "abcdef1234567890abcdef1234567890abcdef12",
+		User:   "Ana",
+		Date:   time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC),
+	}
+
+	got, err := Parse(m.Commit, m.String())
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if got != m {
+		t.Errorf("Expected %+v, got %+v", m, got)
+	}
+}
+
+func TestParse_NotAMark(t *testing.T) {
+	if _, err := Parse("1234567", "Reviewed-by: Bob\n"); err == nil {
+		t.Error("Expected a note without a chr kind to be rejected")
+	}
+}
+
+func TestSet_Lookup(t *testing.T) {
+	set := NewSet([]Mark{
+		{Commit: "aaaa1111bbbb", Kind: KindIgnored},
+		{Commit: "cccc2222dddd", Kind: KindPicked},
+	})
+
+	tests := []struct {
+		hash string
+		kind string
+		ok   bool
+	}{
+		{"aaaa111", KindIgnored, true},
+		{"cccc2222dddd", KindPicked, true},
+		{"aaaa2", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		m, ok := set.Lookup(tt.hash)
+		if ok != tt.ok || m.Kind != tt.kind {
+			t.Errorf("Lookup(%q): expected %q/%v, got %q/%v", tt.hash, tt.kind, tt.ok, m.Kind, ok)
+		}
+	}
+
+	var empty *Set
+	if _, ok := empty.Lookup("aaaa111"); ok || empty.Len() != 0 {
+		t.Error("Expected a nil set to have no marks")
+	}
+}
+
+func TestSaveLoadRemove(t *testing.T) {
+	repoDir := t.TempDir()
+	for _, args := range [][]string{
+		{"init"},
+		{"config", "user.name", "Test User"},
+		{"config", "user.email", "test@example.com"},
+		{"commit", "--allow-empty", "-m", "prd-only config"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = repoDir
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("Failed to get commit hash: %v", err)
+	}
+	hash := strings.TrimSpace(string(output))
+	ctx := context.Background()
+
+	if err := Save(ctx, repoDir, Mark{Commit: hash, Kind: KindIgnored, User: "Ana"}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := Load(ctx, repoDir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(loaded) != 1 || loaded[0].Commit != hash || loaded[0].Kind != KindIgnored || loaded[0].User != "Ana" {
+		t.Fatalf("Expected the saved mark back, got %+v", loaded)
+	}
+
+	if err := Remove(ctx, repoDir, hash); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if loaded, err := Load(ctx, repoDir); err != nil || len(loaded) != 0 {
+		t.Errorf("Expected no marks after Remove, got %+v, %v", loaded, err)
+	}
+}
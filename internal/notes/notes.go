@@ -0,0 +1,148 @@
+package notes
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/carlosarraes/chr/internal/conventional"
+	"github.com/carlosarraes/chr/internal/git"
+	"github.com/carlosarraes/chr/internal/picker"
+)
+
+// Options controls how release notes are rendered
+type Options struct {
+	Title         string
+	TicketPattern *regexp.Regexp // Finds ticket IDs in subjects and trailers
+	TicketURL     string         // URL template where {ticket} is replaced by the ticket ID
+}
+
+// sectionTitles maps commit types to release notes headings, in display order
+var sectionTitles = []struct {
+	Prefix string
+	Title  string
+}{
+	{"feat:", "Features"},
+	{"fix:", "Bug Fixes"},
+	{"perf:", "Performance"},
+	{"refactor:", "Refactoring"},
+	{"revert:", "Reverts"},
+	{"docs:", "Documentation"},
+	{"test:", "Tests"},
+	{"build:", "Build"},
+	{"ci:", "CI"},
+	{"chore:", "Chores"},
+	{"style:", "Style"},
+	{"other:", "Other Changes"},
+}
+
+// Render renders markdown release notes grouped by commit type, followed by the author list
+func Render(commits []git.Commit, opts Options) string {
+	var b strings.Builder
+
+	if opts.Title != "" {
+		fmt.Fprintf(&b, "# %s\n\n", opts.Title)
+	}
+
+	if len(commits) == 0 {
+		b.WriteString("No changes.\n")
+		return b.String()
+	}
+
+	var breaking []git.Commit
+	for _, commit := range commits {
+		if conventional.Parse(commit.FullMessage()).Breaking {
+			breaking = append(breaking, commit)
+		}
+	}
+	if len(breaking) > 0 {
+		b.WriteString("## ⚠ Breaking Changes\n\n")
+		for _, commit := range breaking {
+			b.WriteString(renderEntry(commit, opts))
+		}
+		b.WriteString("\n")
+	}
+
+	groups := picker.GroupCommitsByMessage(commits)
+	sort.SliceStable(groups, func(i, j int) bool {
+		return sectionRank(groups[i].Title) < sectionRank(groups[j].Title)
+	})
+
+	for _, group := range groups {
+		fmt.Fprintf(&b, "## %s\n\n", sectionTitle(group.Title))
+		for _, commit := range group.Commits {
+			b.WriteString(renderEntry(commit, opts))
+		}
+		b.WriteString("\n")
+	}
+
+	summary := picker.SummarizeCommits(commits)
+	authors := make([]string, 0, len(summary.ByAuthor))
+	for author := range summary.ByAuthor {
+		authors = append(authors, author)
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		if summary.ByAuthor[authors[i]] != summary.ByAuthor[authors[j]] {
+			return summary.ByAuthor[authors[i]] > summary.ByAuthor[authors[j]]
+		}
+		return authors[i] < authors[j]
+	})
+
+	b.WriteString("## Authors\n\n")
+	for _, author := range authors {
+		fmt.Fprintf(&b, "- %s (%d)\n", author, summary.ByAuthor[author])
+	}
+
+	return b.String()
+}
+
+// renderEntry renders a single commit as a markdown list item
+func renderEntry(commit git.Commit, opts Options) string {
+	parsed := conventional.Parse(commit.FullMessage())
+
+	entry := parsed.Subject
+	if parsed.Scope != "" {
+		entry = fmt.Sprintf("**%s:** %s", parsed.Scope, entry)
+	}
+
+	if opts.TicketPattern != nil {
+		tickets := picker.ExtractTickets(commit, opts.TicketPattern)
+		if len(tickets) > 0 {
+			links := make([]string, 0, len(tickets))
+			for _, ticket := range tickets {
+				links = append(links, TicketLink(ticket, opts.TicketURL))
+			}
+			entry = fmt.Sprintf("%s (%s)", entry, strings.Join(links, ", "))
+		}
+	}
+
+	return fmt.Sprintf("- %s `%s`\n", entry, commit.Hash)
+}
+
+// TicketLink renders a ticket as a markdown link when a URL template is configured
+func TicketLink(ticket, urlTemplate string) string {
+	if urlTemplate == "" {
+		return ticket
+	}
+	return fmt.Sprintf("[%s](%s)", ticket, strings.ReplaceAll(urlTemplate, "{ticket}", ticket))
+}
+
+func sectionRank(prefix string) int {
+	for i, section := range sectionTitles {
+		if section.Prefix == prefix {
+			return 2 * i
+		}
+	}
+	// Unknown types go right before "Other Changes", the last section
+	return 2*(len(sectionTitles)-1) - 1
+}
+
+func sectionTitle(prefix string) string {
+	for _, section := range sectionTitles {
+		if section.Prefix == prefix {
+			return section.Title
+		}
+	}
+	return strings.TrimSuffix(prefix, ":")
+}
@@ -0,0 +1,61 @@
+package notes
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/carlosarraes/chr/internal/git"
+)
+
+func TestRender(t *testing.T) {
+	commits := []git.Commit{
+		{Hash: "a1", Author: "Alice", Message: "fix(api): handle nil ZUP-12"},
+		{Hash: "b1", Author: "Bob", Message: "feat: add invoices", Body: "Refs: ZUP-456"},
+		{Hash: "a2", Author: "Alice", Message: "feat(ui)!: new layout"},
+		{Hash: "a3", Author: "Alice", Message: "WIP tweak"},
+		{Hash: "b2", Author: "Bob", Message: "security: rotate keys"},
+	}
+
+	output := Render(commits, Options{
+		Title:         "ZUP-1 HML",
+		TicketPattern: regexp.MustCompile(`ZUP-[0-9]+`),
+		TicketURL:     "https://jira.example.com/browse/{ticket}",
+	})
+
+	expected := "# ZUP-1 HML\n\n" +
+		"## ⚠ Breaking Changes\n\n" +
+		"- **ui:** new layout `a2`\n\n" +
+		"## Features\n\n" +
+		"- add invoices ([ZUP-456](https://jira.example.com/browse/ZUP-456)) `b1`\n" +
+		"- **ui:** new layout `a2`\n\n" +
+		"## Bug Fixes\n\n" +
+		"- **api:** handle nil ZUP-12 ([ZUP-12](https://jira.example.com/browse/ZUP-12)) `a1`\n\n" +
+		"## security\n\n" +
+		"- rotate keys `b2`\n\n" +
+		"## Other Changes\n\n" +
+		"- WIP tweak `a3`\n\n" +
+		"## Authors\n\n" +
+		"- Alice (3)\n" +
+		"- Bob (2)\n"
+
+	if output != expected {
+		t.Errorf("Unexpected notes:\n%s\nexpected:\n%s", output, expected)
+	}
+}
+
+func TestRender_Empty(t *testing.T) {
+	output := Render(nil, Options{Title: "Nothing"})
+	if !strings.Contains(output, "No changes.") {
+		t.Errorf("Expected empty notes to say so, got %q", output)
+	}
+}
+
+func TestTicketLink(t *testing.T) {
+	if got := TicketLink("ZUP-1", ""); got != "ZUP-1" {
+		t.Errorf("Expected plain ticket without template, got %q", got)
+	}
+	if got := TicketLink("ZUP-1", "https://t/{ticket}"); got != "[ZUP-1](https://t/ZUP-1)" {
+		t.Errorf("Unexpected link: %q", got)
+	}
+}
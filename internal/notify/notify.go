@@ -0,0 +1,142 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/carlosarraes/chr/internal/httpjson"
+	"github.com/carlosarraes/chr/internal/session"
+)
+
+const (
+	EventPick        = "pick"
+	EventConflict    = "conflict"
+	EventStopped     = "stopped"
+	EventInterrupted = "interrupted"
+	EventFailed      = "failed"
+	EventAborted     = "aborted"
+
+	DefaultTimeout = 5 * time.Second
+	DefaultRetries = 2
+)
+
+// Payload is the JSON body posted to every webhook. Text is rendered by Slack and
+// Teams incoming webhooks; Summary is the one-line variant Teams uses for cards.
+type Payload struct {
+	Event        string                `json:"event"`
+	Session      string                `json:"session"`
+	User         string                `json:"user"`
+	Card         string                `json:"card"`
+	SourceBranch string                `json:"source_branch"`
+	TargetBranch string                `json:"target_branch"`
+	Outcome      string                `json:"outcome"`
+	Commits      []session.ReportEntry `json:"commits"`
+	Text         string                `json:"text"`
+	Summary      string                `json:"summary"`
+}
+
+// NewPayload describes a pick session for the given event
+func NewPayload(event, card string, report session.Report) Payload {
+	summary := fmt.Sprintf("%s: %s → %s by %s (%s)", event, report.SourceBranch, report.TargetBranch, report.User, report.Summary())
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "*chr %s* `%s` → `%s` by %s: %s", event, report.SourceBranch, report.TargetBranch, report.User, report.Summary())
+	for _, entry := range report.Entries {
+		fmt.Fprintf(&text, "\n• `%s` %s (%s)", entry.Source, entry.Subject, entry.Status)
+	}
+
+	return Payload{
+		Event:        event,
+		Session:      report.Session,
+		User:         report.User,
+		Card:         card,
+		SourceBranch: report.SourceBranch,
+		TargetBranch: report.TargetBranch,
+		Outcome:      report.Outcome,
+		Commits:      report.Entries,
+		Text:         text.String(),
+		Summary:      summary,
+	}
+}
+
+// Notifier posts payloads to webhooks with a per-request timeout and bounded retries
+type Notifier struct {
+	URLs    []string
+	Retries int
+	Backoff time.Duration
+	HTTP    *http.Client
+}
+
+// New builds a notifier; a zero timeout uses DefaultTimeout and negative retries none
+func New(urls []string, timeout time.Duration, retries int) *Notifier {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	if retries < 0 {
+		retries = 0
+	}
+	return &Notifier{
+		URLs:    urls,
+		Retries: retries,
+		Backoff: 500 * time.Millisecond,
+		HTTP:    &http.Client{Timeout: timeout},
+	}
+}
+
+// Send posts the payload to every webhook and returns one error per webhook that
+// still failed after the retries. It never panics or blocks beyond the retry budget.
+func (n *Notifier) Send(payload Payload) []error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return []error{fmt.Errorf("failed to encode notification: %w", err)}
+	}
+
+	var errs []error
+	for _, webhook := range n.URLs {
+		if err := n.post(webhook, body); err != nil {
+			errs = append(errs, fmt.Errorf("webhook %s: %w", redactURL(webhook), err))
+		}
+	}
+	return errs
+}
+
+// redactURL keeps the scheme and host of a webhook URL: the path of a Slack or
+// Teams webhook is its secret, so it must not reach errors or logs
+func redactURL(webhook string) string {
+	u, err := url.Parse(webhook)
+	if err != nil || u.Host == "" {
+		return "(invalid URL)"
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// post delivers body to webhook, retrying network errors, 429s and 5xx responses
+func (n *Notifier) post(webhook string, body []byte) error {
+	var lastErr error
+	for attempt := 0; attempt <= n.Retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(n.Backoff * time.Duration(attempt))
+		}
+
+		err := httpjson.Do(n.HTTP, http.MethodPost, webhook, nil, json.RawMessage(body), nil)
+		if err == nil {
+			return nil
+		}
+		// Network errors quote the full URL
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = redactURL(urlErr.URL)
+		}
+		lastErr = err
+		var statusErr *httpjson.StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode != http.StatusTooManyRequests && statusErr.StatusCode < 500 {
+			return err
+		}
+	}
+	return fmt.Errorf("giving up after %d attempts: %w", n.Retries+1, lastErr)
+}
@@ -0,0 +1,133 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/carlosarraes/chr/internal/git"
+	"github.com/carlosarraes/chr/internal/session"
+)
+
+func testReport() session.Report {
+	return session.Report{
+		Session:      "20240115-103000",
+		User:         "Test User",
+		SourceBranch: "ZUP-123-prd",
+		TargetBranch: "ZUP-123-hml",
+		Outcome:      session.OutcomeSuccess,
+		Entries: []session.ReportEntry{
+			{Source: "abc123", Target: "def4567890", Status: git.PickStatusPicked, Author: "Test User", Subject: "feat: add feature"},
+		},
+	}
+}
+
+func TestNewPayload(t *testing.T) {
+	payload := NewPayload(EventPick, "123", testReport())
+
+	if payload.Event != EventPick || payload.Card != "123" || payload.Session != "20240115-103000" {
+		t.Errorf("Unexpected payload: %+v", payload)
+	}
+	if len(payload.Commits) != 1 || payload.Commits[0].Target != "def4567890" {
+		t.Errorf("Expected commits to be carried over, got %+v", payload.Commits)
+	}
+	if !strings.Contains(payload.Text, "`abc123` feat: add feature") {
+		t.Errorf("Expected commit in text, got %q", payload.Text)
+	}
+	if !strings.Contains(payload.Summary, "ZUP-123-prd → ZUP-123-hml") {
+		t.Errorf("Expected branches in summary, got %q", payload.Summary)
+	}
+}
+
+func TestSend_Delivers(t *testing.T) {
+	var got Payload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Unexpected Content-Type %q", ct)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer server.Close()
+
+	n := New([]string{server.URL}, time.Second, 0)
+	if errs := n.Send(NewPayload(EventPick, "123", testReport())); len(errs) != 0 {
+		t.Fatalf("Send failed: %v", errs)
+	}
+	if got.Event != EventPick || got.TargetBranch != "ZUP-123-hml" {
+		t.Errorf("Unexpected delivered payload: %+v", got)
+	}
+}
+
+func TestSend_RetriesServerErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	n := New([]string{server.URL}, time.Second, 2)
+	n.Backoff = time.Millisecond
+	if errs := n.Send(NewPayload(EventPick, "123", testReport())); len(errs) != 0 {
+		t.Fatalf("Expected delivery on the third attempt, got %v", errs)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestSend_BoundedFailures(t *testing.T) {
+	var calls int32
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer rejecting.Close()
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer slow.Close()
+
+	n := New([]string{failing.URL, rejecting.URL, slow.URL}, 50*time.Millisecond, 1)
+	n.Backoff = time.Millisecond
+	errs := n.Send(NewPayload(EventConflict, "123", testReport()))
+	if len(errs) != 3 {
+		t.Fatalf("Expected 3 errors, got %v", errs)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 attempts against the failing webhook, got %d", calls)
+	}
+}
+
+func TestSend_RedactsURLs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "slow") {
+			time.Sleep(200 * time.Millisecond)
+			return
+		}
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	n := New([]string{server.URL + "/services/T000/B000/SECRET", server.URL + "/slow/SECRET"}, 50*time.Millisecond, 0)
+	errs := n.Send(NewPayload(EventPick, "123", testReport()))
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 errors, got %v", errs)
+	}
+	for _, err := range errs {
+		if strings.Contains(err.Error(), "SECRET") || !strings.Contains(err.Error(), server.URL) {
+			t.Errorf("Expected only the scheme and host of the webhook, got %v", err)
+		}
+	}
+}
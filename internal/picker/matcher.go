@@ -0,0 +1,585 @@
+package picker
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/carlosarraes/chr/internal/git"
+	"github.com/carlosarraes/chr/internal/marks"
+)
+
+// Matching strategies: how a source commit is recognized on the target branch
+const (
+	StrategyExact         = "exact"          // Same author, date and subject
+	StrategyAuthorSubject = "author_subject" // Same author and subject, any date
+	StrategyPatchID       = "patch_id"       // Same diff (git patch-id), any message or author
+	StrategyFuzzy         = "fuzzy"          // Same author and a similar subject
+	StrategyTrailer       = "trailer"        // Target says "(cherry picked from commit <source>)", as git cherry-pick -x writes
+
+	// StrategyMarked credits commits marked picked with chr mark-picked, and
+	// StrategyNote the provenance notes chr leaves on the commits it picks.
+	// They always come first, in this order, and can't be configured.
+	StrategyMarked = "marked"
+	StrategyNote   = "note"
+)
+
+// Strategies lists every strategy, in the order they are documented
+var Strategies = []string{StrategyExact, StrategyAuthorSubject, StrategyPatchID, StrategyFuzzy, StrategyTrailer}
+
+// DefaultStrategies are used when none are configured
+var DefaultStrategies = []string{StrategyExact, StrategyAuthorSubject}
+
+// Match scores
+const (
+	ScoreMarked        = 100 // Marked picked by hand
+	ScoreExact         = 100 // Same author, date and subject, or recorded by chr in a provenance note
+	ScoreTrailer       = 100 // Recorded as picked from the source commit
+	ScorePatchID       = 90  // Same diff
+	ScoreAuthorSubject = 80  // Same author and subject, any date
+	ScoreFuzzyMax      = 75  // Fuzzy matches score up to this, by subject similarity
+)
+
+// FuzzyThreshold is the subject similarity (0-1) below which fuzzy matching
+// doesn't pair two commits
+const FuzzyThreshold = 0.8
+
+// CommitMatch represents a match between commits in different branches
+type CommitMatch struct {
+	Source   git.Commit // Commit from source branch (PRD)
+	Target   git.Commit // Matching commit from target branch (HML)
+	Score    int        // Match confidence score (0-100)
+	Strategy string     // Strategy that found the match
+}
+
+// MatchResult splits the source commits into those found on the target and the rest
+type MatchResult struct {
+	Matches   []CommitMatch // In source order
+	Unmatched []git.Commit  // In source order
+	// Duplicates are unmatched source commits whose matching target commits were
+	// all taken by other source commits, e.g. two "fix: lint" commits with only
+	// one picked. Target is the best of them and the commit is also in Unmatched.
+	Duplicates []CommitMatch
+	// Ignored source commits are marked with chr ignore, in source order
+	Ignored []git.Commit
+}
+
+// MatchOptions configures a CommitMatcher
+type MatchOptions struct {
+	// Strategies in priority order; empty uses DefaultStrategies
+	Strategies []string
+	// MinScore is the lowest score that counts a source commit as picked
+	MinScore int
+	// Marks settle source commits without matching: ignored ones are never
+	// unpicked and picked ones pair with their recorded target, if any
+	Marks *marks.Set
+}
+
+// Uses reports whether the options enable a strategy
+func (o MatchOptions) Uses(strategy string) bool {
+	strategies := o.Strategies
+	if len(strategies) == 0 {
+		strategies = DefaultStrategies
+	}
+	for _, s := range strategies {
+		if s == strategy {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseStrategies parses a comma-separated strategy list such as "exact,trailer"
+func ParseStrategies(value string) ([]string, error) {
+	var strategies []string
+	seen := make(map[string]bool)
+	for _, s := range strings.Split(value, ",") {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if s == "" || seen[s] {
+			continue
+		}
+		if !containsFold(Strategies, s) {
+			return nil, fmt.Errorf("unknown matching strategy %q (use %s)", s, strings.Join(Strategies, ", "))
+		}
+		seen[s] = true
+		strategies = append(strategies, s)
+	}
+	if len(strategies) == 0 {
+		return nil, fmt.Errorf("at least one matching strategy is needed (%s)", strings.Join(Strategies, ", "))
+	}
+	return strategies, nil
+}
+
+// CommitMatcher provides commit matching functionality
+type CommitMatcher struct {
+	strategies []string
+	minScore   int
+	marks      *marks.Set
+}
+
+// NewCommitMatcher creates a commit matcher. Unknown strategies are ignored;
+// validate them with ParseStrategies first.
+func NewCommitMatcher(opts MatchOptions) *CommitMatcher {
+	strategies := opts.Strategies
+	if len(strategies) == 0 {
+		strategies = DefaultStrategies
+	}
+	return &CommitMatcher{strategies: strategies, minScore: opts.MinScore, marks: opts.Marks}
+}
+
+// Match pairs source commits with the target commits they were picked as. This
+// is the core function that solves the rebase hash-change problem.
+//
+// Strategies are tried in order and a pair is credited to the first one that
+// finds it with at least the minimum score. Each target commit is used at most
+// once: pairs found by earlier strategies are assigned first. Within a strategy
+// as many sources as possible are paired, preferring the highest scores, then
+// the closest dates. The target commits are indexed once, so only commits
+// sharing a key are ever compared.
+//
+// Marked source commits skip matching: ignored ones are reported apart and
+// picked ones pair with their recorded target before any strategy runs.
+// Target commits whose provenance notes name a source commit pair with it
+// next, as exact matches; a squashed target pairs with each commit it names.
+func (cm *CommitMatcher) Match(sourceCommits, targetCommits []git.Commit) MatchResult {
+	index := newTargetIndex(targetCommits, cm.strategies)
+
+	var candidates []candidate
+	marked := make(map[int]marks.Mark)
+	for i, source := range sourceCommits {
+		if m, ok := cm.marks.Lookup(source.Hash); ok {
+			marked[i] = m
+			if target := index.find(m.Target); m.Kind == marks.KindPicked && target >= 0 {
+				candidates = append(candidates, candidate{source: i, target: target, rank: rankMarked, score: ScoreMarked})
+			}
+			continue
+		}
+		candidates = cm.appendCandidates(candidates, index, i, source)
+	}
+	// Stable, so equal pairs keep source order, then target order
+	sort.SliceStable(candidates, func(a, b int) bool {
+		if candidates[a].rank != candidates[b].rank {
+			return candidates[a].rank < candidates[b].rank
+		}
+		if candidates[a].score != candidates[b].score {
+			return candidates[a].score > candidates[b].score
+		}
+		return candidates[a].distance < candidates[b].distance
+	})
+
+	assigned := make(map[int]candidate, len(sourceCommits))
+	best := make(map[int]candidate, len(sourceCommits))
+	owner := make([]int, len(targetCommits))
+	for i := range owner {
+		owner[i] = -1
+	}
+	for start := 0; start < len(candidates); {
+		end := start + 1
+		for end < len(candidates) && candidates[end].rank == candidates[start].rank {
+			end++
+		}
+		assignRank(candidates[start:end], assigned, best, owner)
+		start = end
+	}
+
+	var result MatchResult
+	for i, source := range sourceCommits {
+		if c, ok := assigned[i]; ok {
+			result.Matches = append(result.Matches, cm.commitMatch(source, targetCommits[c.target], c))
+			continue
+		}
+		if m, ok := marked[i]; ok {
+			if m.Kind == marks.KindIgnored {
+				result.Ignored = append(result.Ignored, source)
+				continue
+			}
+			// Picked without a target, or as a commit outside targetCommits
+			result.Matches = append(result.Matches, CommitMatch{
+				Source: source, Target: git.Commit{Hash: m.Target}, Score: ScoreMarked, Strategy: StrategyMarked,
+			})
+			continue
+		}
+		result.Unmatched = append(result.Unmatched, source)
+		if c, ok := best[i]; ok {
+			result.Duplicates = append(result.Duplicates, cm.commitMatch(source, targetCommits[c.target], c))
+		}
+	}
+	return result
+}
+
+// assignRank pairs the candidates found at one rank, sorted by preference, on
+// top of the pairs assigned at earlier ranks. Pairs are taken greedily, then
+// augmenting paths move earlier pairs of this rank to their other targets
+// whenever that frees a target for a source left without one, so as many
+// sources as possible are paired. owner maps a target to its source, or -1.
+func assignRank(candidates []candidate, assigned, best map[int]candidate, owner []int) {
+	rank := candidates[0].rank
+	for _, c := range candidates {
+		if _, ok := best[c.source]; !ok {
+			best[c.source] = c
+		}
+		// A squashed commit's notes name every commit that went into it, so
+		// note pairs may share their target
+		if _, ok := assigned[c.source]; ok || (owner[c.target] >= 0 && rank != rankNote) {
+			continue
+		}
+		assigned[c.source] = c
+		owner[c.target] = c.source
+	}
+	if rank == rankNote {
+		return
+	}
+
+	edges := make(map[int][]candidate)
+	var waiting []int
+	for _, c := range candidates {
+		if a, ok := assigned[c.source]; ok && a.rank != rank {
+			continue
+		}
+		if _, ok := edges[c.source]; !ok {
+			if _, ok := assigned[c.source]; !ok {
+				waiting = append(waiting, c.source)
+			}
+		}
+		edges[c.source] = append(edges[c.source], c)
+	}
+
+	var augment func(source int, visited map[int]bool) bool
+	augment = func(source int, visited map[int]bool) bool {
+		for _, c := range edges[source] {
+			if visited[c.target] {
+				continue
+			}
+			visited[c.target] = true
+			holder := owner[c.target]
+			if holder >= 0 && assigned[holder].rank != rank {
+				continue // Paired at an earlier rank
+			}
+			if holder < 0 || augment(holder, visited) {
+				owner[c.target] = source
+				assigned[source] = c
+				return true
+			}
+		}
+		return false
+	}
+	for _, source := range waiting {
+		augment(source, make(map[int]bool))
+	}
+}
+
+func (cm *CommitMatcher) commitMatch(source, target git.Commit, c candidate) CommitMatch {
+	return CommitMatch{Source: source, Target: target, Score: c.score, Strategy: cm.strategy(c.rank)}
+}
+
+// strategy names the strategy at a candidate rank
+func (cm *CommitMatcher) strategy(rank int) string {
+	switch rank {
+	case rankMarked:
+		return StrategyMarked
+	case rankNote:
+		return StrategyNote
+	}
+	return cm.strategies[rank]
+}
+
+// FindMatches finds matching commits between source and target lists
+func (cm *CommitMatcher) FindMatches(sourceCommits, targetCommits []git.Commit) []CommitMatch {
+	return cm.Match(sourceCommits, targetCommits).Matches
+}
+
+// GetUnmatched returns commits from source that don't have matches in target
+func (cm *CommitMatcher) GetUnmatched(sourceCommits, targetCommits []git.Commit) []git.Commit {
+	return cm.Match(sourceCommits, targetCommits).Unmatched
+}
+
+// Pairs recorded with chr mark-picked and in provenance notes rank ahead of
+// every configured strategy
+const (
+	rankMarked = -2
+	rankNote   = -1
+)
+
+// candidate is a possible pairing of a source and a target commit, by index
+type candidate struct {
+	source   int
+	target   int
+	rank     int // Position of the strategy that found it
+	score    int
+	distance int // Days between the two commit dates
+}
+
+// appendCandidates adds every target commit matching source, credited to the
+// first strategy that matches it well enough
+func (cm *CommitMatcher) appendCandidates(candidates []candidate, index *targetIndex, sourceIndex int, source git.Commit) []candidate {
+	sourceDay := dayNumber(source.Date)
+	seen := make(map[int]bool)
+	add := func(rank, target, score int) {
+		if score < cm.minScore || seen[target] {
+			return
+		}
+		seen[target] = true
+		candidates = append(candidates, candidate{
+			source:   sourceIndex,
+			target:   target,
+			rank:     rank,
+			score:    score,
+			distance: dayDistance(sourceDay, index.days[target]),
+		})
+	}
+
+	if len(source.Hash) >= hashKeyLength {
+		for _, target := range index.byNote[source.Hash[:hashKeyLength]] {
+			for _, from := range index.targets[target].PickedFrom {
+				if sameHash(from, source.Hash) {
+					add(rankNote, target, ScoreExact)
+				}
+			}
+		}
+	}
+
+	for rank, strategy := range cm.strategies {
+		switch strategy {
+		case StrategyExact:
+			// Handles rebases where content is identical
+			for _, target := range index.bySignature[signatureOf(source)] {
+				add(rank, target, ScoreExact)
+			}
+		case StrategyAuthorSubject:
+			// Handles commits cherry-picked on different dates
+			for _, target := range index.byAuthorSubject[authorSubjectOf(source)] {
+				add(rank, target, ScoreAuthorSubject)
+			}
+		case StrategyPatchID:
+			// Handles reworded commits; needs git.LoadPatchIDs on both sides
+			if source.PatchID != "" {
+				for _, target := range index.byPatchID[source.PatchID] {
+					add(rank, target, ScorePatchID)
+				}
+			}
+		case StrategyTrailer:
+			if len(source.Hash) >= hashKeyLength {
+				for _, target := range index.byTrailer[source.Hash[:hashKeyLength]] {
+					if pickedFrom(index.targets[target], source.Hash) {
+						add(rank, target, ScoreTrailer)
+					}
+				}
+			}
+		case StrategyFuzzy:
+			subject := normalizeSubject(source.Message)
+			for _, target := range index.byAuthor[source.Author] {
+				if similarity := subjectSimilarity(subject, index.subjects[target]); similarity >= FuzzyThreshold {
+					add(rank, target, int(math.Round(similarity*ScoreFuzzyMax)))
+				}
+			}
+		}
+	}
+
+	return candidates
+}
+
+// signature identifies commits by author, date and first message line, like
+// git.Commit.Signature without formatting a string for every commit
+type signature struct {
+	author  string
+	date    string
+	subject string
+}
+
+// authorSubject identifies commits by author and first message line, ignoring the date
+type authorSubject struct {
+	author  string
+	subject string
+}
+
+func signatureOf(c git.Commit) signature {
+	return signature{author: c.Author, date: c.Date, subject: firstLine(c.Message)}
+}
+
+func authorSubjectOf(c git.Commit) authorSubject {
+	return authorSubject{author: c.Author, subject: strings.TrimSpace(firstLine(c.Message))}
+}
+
+func firstLine(message string) string {
+	line, _, _ := strings.Cut(message, "\n")
+	return line
+}
+
+// cherryPickedFrom matches the line git cherry-pick -x appends to a message
+var cherryPickedFrom = regexp.MustCompile(`\(cherry picked from commit ([0-9a-f]{7,40})\)`)
+
+// hashKeyLength is how many hex digits of a hash key the trailer and note indexes
+const hashKeyLength = 7
+
+// pickedFromHashes returns the commits a commit says it was cherry-picked from
+func pickedFromHashes(c git.Commit) []string {
+	var hashes []string
+	for _, m := range cherryPickedFrom.FindAllStringSubmatch(c.FullMessage(), -1) {
+		hashes = append(hashes, m[1])
+	}
+	return hashes
+}
+
+// pickedFrom reports whether target says it was cherry-picked from hash, which
+// may be abbreviated
+func pickedFrom(target git.Commit, hash string) bool {
+	for _, from := range pickedFromHashes(target) {
+		if sameHash(from, hash) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameHash reports whether two hashes, either of them abbreviated, name the same commit
+func sameHash(a, b string) bool {
+	return a != "" && b != "" && (strings.HasPrefix(a, b) || strings.HasPrefix(b, a))
+}
+
+// targetIndex maps the keys of each enabled strategy to the target commits
+// with that key, in target order
+type targetIndex struct {
+	targets         []git.Commit
+	days            []int
+	subjects        []string // Normalized subjects, for fuzzy matching
+	bySignature     map[signature][]int
+	byAuthorSubject map[authorSubject][]int
+	byPatchID       map[string][]int
+	byTrailer       map[string][]int // By the first hashKeyLength digits of the source hash
+	byNote          map[string][]int // Likewise, from provenance notes
+	byHash          map[string][]int // Likewise, from the target commit's own hash
+	byAuthor        map[string][]int
+}
+
+func newTargetIndex(targetCommits []git.Commit, strategies []string) *targetIndex {
+	index := &targetIndex{
+		targets:         targetCommits,
+		days:            make([]int, len(targetCommits)),
+		subjects:        make([]string, len(targetCommits)),
+		bySignature:     make(map[signature][]int),
+		byAuthorSubject: make(map[authorSubject][]int),
+		byPatchID:       make(map[string][]int),
+		byTrailer:       make(map[string][]int),
+		byNote:          make(map[string][]int),
+		byHash:          make(map[string][]int),
+		byAuthor:        make(map[string][]int),
+	}
+	uses := MatchOptions{Strategies: strategies}.Uses
+
+	for i, target := range targetCommits {
+		index.days[i] = dayNumber(target.Date)
+		if len(target.Hash) >= hashKeyLength {
+			index.byHash[target.Hash[:hashKeyLength]] = append(index.byHash[target.Hash[:hashKeyLength]], i)
+		}
+		if uses(StrategyExact) {
+			index.bySignature[signatureOf(target)] = append(index.bySignature[signatureOf(target)], i)
+		}
+		if uses(StrategyAuthorSubject) {
+			index.byAuthorSubject[authorSubjectOf(target)] = append(index.byAuthorSubject[authorSubjectOf(target)], i)
+		}
+		if uses(StrategyPatchID) && target.PatchID != "" {
+			index.byPatchID[target.PatchID] = append(index.byPatchID[target.PatchID], i)
+		}
+		for _, from := range target.PickedFrom {
+			if len(from) >= hashKeyLength {
+				index.byNote[from[:hashKeyLength]] = append(index.byNote[from[:hashKeyLength]], i)
+			}
+		}
+		if uses(StrategyTrailer) {
+			for _, from := range pickedFromHashes(target) {
+				index.byTrailer[from[:hashKeyLength]] = append(index.byTrailer[from[:hashKeyLength]], i)
+			}
+		}
+		if uses(StrategyFuzzy) {
+			index.subjects[i] = normalizeSubject(target.Message)
+			index.byAuthor[target.Author] = append(index.byAuthor[target.Author], i)
+		}
+	}
+	return index
+}
+
+// find returns the index of the target commit with a full hash, or -1
+func (index *targetIndex) find(hash string) int {
+	if len(hash) < hashKeyLength {
+		return -1
+	}
+	for _, i := range index.byHash[hash[:hashKeyLength]] {
+		if strings.HasPrefix(hash, index.targets[i].Hash) {
+			return i
+		}
+	}
+	return -1
+}
+
+// normalizeSubject lowercases a commit subject and collapses its whitespace
+func normalizeSubject(message string) string {
+	return strings.Join(strings.Fields(strings.ToLower(firstLine(message))), " ")
+}
+
+// subjectSimilarity returns 1 minus the edit distance between two subjects
+// relative to the longer one: 1 for equal subjects, 0 for unrelated ones
+func subjectSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+
+	// The distance is at least the length difference: skip hopeless pairs early
+	shortest := len(ra) + len(rb) - longest
+	if float64(shortest)/float64(longest) < FuzzyThreshold {
+		return float64(shortest) / float64(longest)
+	}
+
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// unknownDay marks a date that could not be parsed
+const unknownDay = math.MinInt
+
+// dayNumber converts a YYYY-MM-DD date to days since the Unix epoch
+func dayNumber(date string) int {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return unknownDay
+	}
+	return int(t.Unix() / 86400)
+}
+
+// dayDistance returns how many days apart two dates are; unknown dates are as far as possible
+func dayDistance(a, b int) int {
+	if a == unknownDay || b == unknownDay {
+		return math.MaxInt
+	}
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
@@ -0,0 +1,355 @@
+package picker
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/carlosarraes/chr/internal/git"
+	"github.com/carlosarraes/chr/internal/marks"
+)
+
+func TestCommitMatcher_Match(t *testing.T) {
+	source := []git.Commit{
+		{Hash: "s1", Author: "Ana", Message: "feat: add login", Date: "2024-01-01"},
+		{Hash: "s2", Author: "Ana", Message: "fix: typo", Date: "2024-01-02"},
+		{Hash: "s3", Author: "Bob", Message: "chore: bump deps", Date: "2024-01-03"},
+	}
+	target := []git.Commit{
+		{Hash: "t1", Author: "Ana", Message: "feat: add login", Date: "2024-01-05"},
+		{Hash: "t2", Author: "Ana", Message: "feat: add login", Date: "2024-01-01"},
+		{Hash: "t3", Author: "Ana", Message: "fix: typo  ", Date: "2024-01-09"},
+	}
+
+	result := NewCommitMatcher(MatchOptions{}).Match(source, target)
+
+	want := []struct {
+		source, target string
+		score          int
+	}{
+		{"s1", "t2", ScoreExact}, // The exact match wins over the earlier author+subject one
+		{"s2", "t3", ScoreAuthorSubject},
+	}
+	if len(result.Matches) != len(want) {
+		t.Fatalf("Expected %d matches, got %+v", len(want), result.Matches)
+	}
+	for i, w := range want {
+		m := result.Matches[i]
+		if m.Source.Hash != w.source || m.Target.Hash != w.target || m.Score != w.score {
+			t.Errorf("Match %d: expected %s → %s (%d), got %s → %s (%d)",
+				i, w.source, w.target, w.score, m.Source.Hash, m.Target.Hash, m.Score)
+		}
+	}
+	if len(result.Unmatched) != 1 || result.Unmatched[0].Hash != "s3" {
+		t.Errorf("Expected s3 to stay unmatched, got %+v", result.Unmatched)
+	}
+}
+
+func TestCommitMatcher_MatchOneToOne(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     []git.Commit
+		target     []git.Commit
+		want       map[string]string // Source hash → target hash
+		duplicates []string
+	}{
+		{
+			name: "same subject picked once",
+			source: []git.Commit{
+				{Hash: "s2", Author: "Ana", Message: "fix: lint", Date: "2024-01-10"},
+				{Hash: "s1", Author: "Ana", Message: "fix: lint", Date: "2024-01-02"},
+			},
+			target: []git.Commit{
+				{Hash: "t1", Author: "Ana", Message: "fix: lint", Date: "2024-01-03"},
+			},
+			want:       map[string]string{"s1": "t1"}, // Closest date wins
+			duplicates: []string{"s2"},
+		},
+		{
+			name: "exact match before date proximity",
+			source: []git.Commit{
+				{Hash: "s2", Author: "Ana", Message: "fix: lint", Date: "2024-01-04"},
+				{Hash: "s1", Author: "Ana", Message: "fix: lint", Date: "2024-01-01"},
+			},
+			target: []git.Commit{
+				{Hash: "t2", Author: "Ana", Message: "fix: lint", Date: "2024-01-05"},
+				{Hash: "t1", Author: "Ana", Message: "fix: lint", Date: "2024-01-04"},
+			},
+			want: map[string]string{"s2": "t1", "s1": "t2"},
+		},
+		{
+			name: "both picked",
+			source: []git.Commit{
+				{Hash: "s2", Author: "Ana", Message: "fix: lint", Date: "2024-01-10"},
+				{Hash: "s1", Author: "Ana", Message: "fix: lint", Date: "2024-01-02"},
+			},
+			target: []git.Commit{
+				{Hash: "t2", Author: "Ana", Message: "fix: lint", Date: "2024-01-11"},
+				{Hash: "t1", Author: "Ana", Message: "fix: lint", Date: "2024-01-03"},
+			},
+			want: map[string]string{"s2": "t2", "s1": "t1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewCommitMatcher(MatchOptions{}).Match(tt.source, tt.target)
+
+			got := make(map[string]string)
+			for _, m := range result.Matches {
+				got[m.Source.Hash] = m.Target.Hash
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected matches %v, got %v", tt.want, got)
+			}
+
+			var duplicates []string
+			for _, d := range result.Duplicates {
+				duplicates = append(duplicates, d.Source.Hash)
+			}
+			if !reflect.DeepEqual(duplicates, tt.duplicates) {
+				t.Errorf("Expected duplicates %v, got %v", tt.duplicates, duplicates)
+			}
+			if len(result.Matches)+len(result.Unmatched) != len(tt.source) {
+				t.Errorf("Expected every source commit to be matched or unmatched, got %+v", result)
+			}
+		})
+	}
+}
+
+func TestCommitMatcher_MatchMaximizesPairs(t *testing.T) {
+	// Greedily, a takes t1, its best match, and leaves b without a target.
+	// a also matches t2, so moving it there pairs both.
+	source := []git.Commit{
+		{Hash: "aaaaaaa1", Author: "Ana", Message: "fix: handle empty cart", Date: "2024-01-01"},
+		{Hash: "bbbbbbb2", Author: "Ana", Message: "fix: handle empty ca", Date: "2024-01-02"},
+	}
+	target := []git.Commit{
+		{Hash: "t1", Author: "Ana", Message: "fix: handle empty cart", Date: "2024-01-05"},
+		{Hash: "t2", Author: "Ana", Message: "fix: handle empty cart page", Date: "2024-01-06"},
+	}
+
+	result := NewCommitMatcher(MatchOptions{Strategies: []string{StrategyFuzzy}}).Match(source, target)
+	got := make(map[string]string)
+	for _, m := range result.Matches {
+		got[m.Source.Hash] = m.Target.Hash
+	}
+	want := map[string]string{"aaaaaaa1": "t2", "bbbbbbb2": "t1"}
+	if !reflect.DeepEqual(got, want) || len(result.Unmatched) != 0 {
+		t.Errorf("Expected %v with nothing unmatched, got %v and %d unmatched", want, got, len(result.Unmatched))
+	}
+}
+
+func TestCommitMatcher_MatchStrategies(t *testing.T) {
+	source := []git.Commit{
+		{Hash: "aaaaaaa1", Author: "Ana", Message: "feat: add login", Date: "2024-01-01", PatchID: "p1"},
+		{Hash: "bbbbbbb2", Author: "Ana", Message: "fix: handle empty cart", Date: "2024-01-02"},
+		{Hash: "ccccccc3", Author: "Bob", Message: "chore: bump deps", Date: "2024-01-03"},
+	}
+	target := []git.Commit{
+		{Hash: "t1", Author: "Cid", Message: "Add login page", Date: "2024-01-05", PatchID: "p1"},
+		{Hash: "t2", Author: "Ana", Message: "fix: handle empty carts", Date: "2024-01-06"},
+		{Hash: "t3", Author: "Bob", Message: "chore: update deps", Date: "2024-01-07",
+			Body: "(cherry picked from commit ccccccc3d4e5f60718293a4b5c6d7e8f90a1b2c3)"},
+	}
+
+	tests := []struct {
+		name string
+		opts MatchOptions
+		want map[string]string // Source hash → strategy
+	}{
+		{"defaults", MatchOptions{}, map[string]string{}},
+		{"patch_id", MatchOptions{Strategies: []string{StrategyPatchID}}, map[string]string{"aaaaaaa1": StrategyPatchID}},
+		{"fuzzy", MatchOptions{Strategies: []string{StrategyFuzzy}}, map[string]string{"bbbbbbb2": StrategyFuzzy}},
+		{"trailer", MatchOptions{Strategies: []string{StrategyTrailer}}, map[string]string{"ccccccc3": StrategyTrailer}},
+		{"min score", MatchOptions{Strategies: []string{StrategyPatchID, StrategyFuzzy}, MinScore: 80}, map[string]string{"aaaaaaa1": StrategyPatchID}},
+		{
+			"all",
+			MatchOptions{Strategies: Strategies},
+			map[string]string{"aaaaaaa1": StrategyPatchID, "bbbbbbb2": StrategyFuzzy, "ccccccc3": StrategyTrailer},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string]string)
+			for _, m := range NewCommitMatcher(tt.opts).Match(source, target).Matches {
+				got[m.Source.Hash] = m.Strategy
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCommitMatcher_StrategyOrder(t *testing.T) {
+	source := []git.Commit{{Hash: "s1", Author: "Ana", Message: "fix: lint", Date: "2024-01-01", PatchID: "p1"}}
+	target := []git.Commit{
+		{Hash: "t1", Author: "Ana", Message: "fix: lint", Date: "2024-01-01"},
+		{Hash: "t2", Author: "Ana", Message: "style: lint", Date: "2024-01-02", PatchID: "p1"},
+	}
+
+	tests := []struct {
+		strategies []string
+		target     string
+		score      int
+	}{
+		{[]string{StrategyExact, StrategyPatchID}, "t1", ScoreExact},
+		{[]string{StrategyPatchID, StrategyExact}, "t2", ScorePatchID},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.strategies, ","), func(t *testing.T) {
+			matches := NewCommitMatcher(MatchOptions{Strategies: tt.strategies}).FindMatches(source, target)
+			if len(matches) != 1 || matches[0].Target.Hash != tt.target || matches[0].Score != tt.score {
+				t.Errorf("Expected s1 → %s (%d), got %+v", tt.target, tt.score, matches)
+			}
+		})
+	}
+}
+
+func TestCommitMatcher_Marks(t *testing.T) {
+	source := []git.Commit{
+		{Hash: "aaaaaaa", Author: "Ana", Message: "chore: prd-only config", Date: "2024-01-01"},
+		{Hash: "bbbbbbb", Author: "Ana", Message: "fix: lint", Date: "2024-01-02"},
+		{Hash: "ccccccc", Author: "Ana", Message: "fix: lint", Date: "2024-01-03"},
+		{Hash: "ddddddd", Author: "Ana", Message: "feat: squashed away", Date: "2024-01-04"},
+	}
+	target := []git.Commit{
+		{Hash: "1111111", Author: "Ana", Message: "fix: lint", Date: "2024-01-03"},
+	}
+	set := marks.NewSet([]marks.Mark{
+		{Commit: "aaaaaaa0000", Kind: marks.KindIgnored},
+		{Commit: "bbbbbbb0000", Kind: marks.KindPicked, Target: "11111110000"},
+		{Commit: "ddddddd0000", Kind: marks.KindPicked},
+	})
+
+	result := NewCommitMatcher(MatchOptions{Marks: set}).Match(source, target)
+
+	got := make(map[string]string)
+	for _, m := range result.Matches {
+		got[m.Source.Hash] = m.Strategy + "→" + m.Target.Hash
+	}
+	// The mark claims the target first, so the exact match for ccccccc loses it
+	want := map[string]string{"bbbbbbb": StrategyMarked + "→1111111", "ddddddd": StrategyMarked + "→"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected matches %v, got %v", want, got)
+	}
+	if len(result.Ignored) != 1 || result.Ignored[0].Hash != "aaaaaaa" {
+		t.Errorf("Expected aaaaaaa to be ignored, got %+v", result.Ignored)
+	}
+	if len(result.Unmatched) != 1 || result.Unmatched[0].Hash != "ccccccc" {
+		t.Errorf("Expected only ccccccc to stay unpicked, got %+v", result.Unmatched)
+	}
+}
+
+func TestCommitMatcher_Notes(t *testing.T) {
+	source := []git.Commit{
+		{Hash: "aaaaaaa", Author: "Ana", Message: "feat: login", Date: "2024-01-01"},
+		{Hash: "bbbbbbb", Author: "Ana", Message: "feat: logout", Date: "2024-01-02"},
+		{Hash: "ccccccc", Author: "Ana", Message: "fix: lint", Date: "2024-01-03"},
+		{Hash: "ddddddd", Author: "Ana", Message: "fix: lint", Date: "2024-01-04"},
+	}
+	target := []git.Commit{
+		// Login and logout squashed and reworded after the pick
+		{Hash: "1111111", Author: "Ana", Message: "feat: sessions", Date: "2024-02-01",
+			PickedFrom: []string{"aaaaaaa0000", "bbbbbbb0000"}},
+		{Hash: "2222222", Author: "Ana", Message: "fix: lint", Date: "2024-01-03", PickedFrom: []string{"ddddddd0000"}},
+	}
+
+	result := NewCommitMatcher(MatchOptions{}).Match(source, target)
+
+	got := make(map[string]string)
+	for _, m := range result.Matches {
+		got[m.Source.Hash] = m.Strategy + "→" + m.Target.Hash
+	}
+	// The note claims 2222222, so the exact match for ccccccc loses it
+	want := map[string]string{
+		"aaaaaaa": StrategyNote + "→1111111",
+		"bbbbbbb": StrategyNote + "→1111111",
+		"ddddddd": StrategyNote + "→2222222",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected matches %v, got %v", want, got)
+	}
+	if len(result.Unmatched) != 1 || result.Unmatched[0].Hash != "ccccccc" {
+		t.Errorf("Expected only ccccccc to stay unpicked, got %+v", result.Unmatched)
+	}
+}
+
+func TestParseStrategies(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{"exact", []string{"exact"}, false},
+		{" Patch_ID , exact,patch_id", []string{"patch_id", "exact"}, false},
+		{"exact,nope", nil, true},
+		{" , ", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseStrategies(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSubjectSimilarity(t *testing.T) {
+	if got := subjectSimilarity("fix: typo", "fix: typo"); got != 1 {
+		t.Errorf("Expected equal subjects to be 1, got %v", got)
+	}
+	if got := subjectSimilarity("fix: handle empty cart", "fix: handle empty carts"); got < FuzzyThreshold {
+		t.Errorf("Expected a one-letter change to be similar, got %v", got)
+	}
+	if got := subjectSimilarity("feat: add login", "chore: bump deps"); got >= FuzzyThreshold {
+		t.Errorf("Expected unrelated subjects to differ, got %v", got)
+	}
+}
+
+// benchmarkCommits builds n source and n target commits: half the source
+// commits were picked, a quarter of those on a later date
+func benchmarkCommits(n int) (source, target []git.Commit) {
+	for i := 0; i < n; i++ {
+		author := fmt.Sprintf("Author %d", i%25)
+		date := fmt.Sprintf("2024-%02d-%02d", i%12+1, i%28+1)
+		commit := git.Commit{Hash: fmt.Sprintf("s%07x", i), Author: author, Message: fmt.Sprintf("feat: change %d", i), Date: date}
+		source = append(source, commit)
+
+		if i%2 == 0 {
+			picked := commit
+			picked.Hash = fmt.Sprintf("t%07x", i)
+			if i%8 == 0 {
+				picked.Date = "2025-01-01"
+			}
+			target = append(target, picked)
+		} else {
+			target = append(target, git.Commit{Hash: fmt.Sprintf("u%07x", i), Author: author, Message: fmt.Sprintf("fix: target only %d", i), Date: date})
+		}
+	}
+	return source, target
+}
+
+func BenchmarkCommitMatcher_Match(b *testing.B) {
+	for _, n := range []int{1000, 5000, 20000} {
+		source, target := benchmarkCommits(n)
+		b.Run(fmt.Sprintf("commits=%d", n), func(b *testing.B) {
+			matcher := NewCommitMatcher(MatchOptions{})
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if result := matcher.Match(source, target); len(result.Matches) != n/2 {
+					b.Fatalf("Expected %d matches, got %d", n/2, len(result.Matches))
+				}
+			}
+		})
+	}
+}
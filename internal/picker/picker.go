@@ -1,134 +1,46 @@
 package picker
 
 import (
-	"fmt"
+	"errors"
+	"log/slog"
+	"regexp"
 	"strings"
 
+	"github.com/carlosarraes/chr/internal/conventional"
 	"github.com/carlosarraes/chr/internal/git"
+	"github.com/carlosarraes/chr/internal/marks"
 )
 
-// CommitMatch represents a match between commits in different branches
-type CommitMatch struct {
-	Source git.Commit // Commit from source branch (PRD)
-	Target git.Commit // Matching commit from target branch (HML)
-	Score  int        // Match confidence score (0-100)
-}
-
-// CommitMatcher provides commit matching functionality
-type CommitMatcher struct {
-	// Future: could add configuration for matching strategies
-}
-
-// NewCommitMatcher creates a new commit matcher
-func NewCommitMatcher() *CommitMatcher {
-	return &CommitMatcher{}
-}
-
-// FindMatches finds matching commits between source and target lists
-// This is the core function that solves the rebase hash-change problem
-func (cm *CommitMatcher) FindMatches(sourceCommits, targetCommits []git.Commit) []CommitMatch {
-	var matches []CommitMatch
-
-	for _, sourceCommit := range sourceCommits {
-		for _, targetCommit := range targetCommits {
-			if match, score := cm.matchCommits(sourceCommit, targetCommit); match {
-				matches = append(matches, CommitMatch{
-					Source: sourceCommit,
-					Target: targetCommit,
-					Score:  score,
-				})
-				break // Only find first match for each source commit
-			}
-		}
-	}
-
-	return matches
-}
-
-// GetUnmatched returns commits from source that don't have matches in target
-func (cm *CommitMatcher) GetUnmatched(sourceCommits, targetCommits []git.Commit) []git.Commit {
-	matches := cm.FindMatches(sourceCommits, targetCommits)
-	matchedHashes := make(map[string]bool)
-
-	for _, match := range matches {
-		matchedHashes[match.Source.Hash] = true
-	}
-
-	var unmatched []git.Commit
-	for _, commit := range sourceCommits {
-		if !matchedHashes[commit.Hash] {
-			unmatched = append(unmatched, commit)
-		}
-	}
-
-	return unmatched
-}
-
-// matchCommits determines if two commits match using various strategies
-func (cm *CommitMatcher) matchCommits(source, target git.Commit) (bool, int) {
-	// Strategy 1: Exact signature match (author + date + message)
-	// This handles rebases where content is identical
-	if source.Signature() == target.Signature() {
-		return true, 100
-	}
-
-	// Strategy 2: Message + Author match (ignoring date)
-	// This handles cases where commits are cherry-picked on different dates
-	if cm.sameAuthorAndMessage(source, target) {
-		return true, 80
-	}
-
-	// Strategy 3: Fuzzy message matching (future enhancement)
-	// Could implement Levenshtein distance or other algorithms
-
-	return false, 0
-}
-
-// sameAuthorAndMessage checks if two commits have the same author and message
-func (cm *CommitMatcher) sameAuthorAndMessage(c1, c2 git.Commit) bool {
-	if c1.Author != c2.Author {
-		return false
-	}
-
-	// Compare first line of commit messages (ignore multiline descriptions)
-	msg1 := strings.Split(c1.Message, "\n")[0]
-	msg2 := strings.Split(c2.Message, "\n")[0]
-
-	return strings.TrimSpace(msg1) == strings.TrimSpace(msg2)
-}
+// ErrNothingToPick is returned when no commit is left to pick after filtering
+var ErrNothingToPick = errors.New("nothing to pick")
 
 // FilterUnpickedCommits returns commits from PRD that haven't been picked to HML
 // This is the main function used by the CLI to find commits to cherry-pick
-func FilterUnpickedCommits(prdCommits, hmlCommits []git.Commit, debug bool) []git.Commit {
-	if len(hmlCommits) == 0 {
-		// If HML is empty, all PRD commits are unpicked
-		return prdCommits
+func FilterUnpickedCommits(prdCommits, hmlCommits []git.Commit, opts MatchOptions, log *slog.Logger) []git.Commit {
+	log.Debug("filtering commits", "source", len(prdCommits), "target", len(hmlCommits))
+
+	result := NewCommitMatcher(opts).Match(prdCommits, hmlCommits)
+
+	log.Debug("found matches", "count", len(result.Matches))
+	for _, match := range result.Matches {
+		log.Debug("match",
+			"source", match.Source.Hash, "source_subject", match.Source.Message,
+			"target", match.Target.Hash, "target_subject", match.Target.Message,
+			"score", match.Score, "strategy", match.Strategy)
 	}
 
-	if debug {
-		fmt.Printf("Debug: Filtering %d PRD commits against %d HML commits\n", len(prdCommits), len(hmlCommits))
+	for _, duplicate := range result.Duplicates {
+		log.Warn("commit matches a target commit already paired with another commit; treating it as unpicked",
+			"commit", duplicate.Source.Hash, "subject", duplicate.Source.Message, "target", duplicate.Target.Hash)
 	}
 
-	matcher := NewCommitMatcher()
-	matches := matcher.FindMatches(prdCommits, hmlCommits)
-	
-	if debug {
-		fmt.Printf("Debug: Found %d matches:\n", len(matches))
-		for _, match := range matches {
-			fmt.Printf("  PRD: %s (%s) -> HML: %s (%s) [score: %d]\n", 
-				match.Source.Hash, match.Source.Message, 
-				match.Target.Hash, match.Target.Message, 
-				match.Score)
-		}
+	for _, commit := range result.Ignored {
+		log.Debug("ignored commit", "commit", commit.Hash, "subject", commit.Message)
 	}
 	
-	unmatched := matcher.GetUnmatched(prdCommits, hmlCommits)
-	
-	if debug {
-		fmt.Printf("Debug: %d unmatched commits remain\n", len(unmatched))
-	}
+	log.Debug("unmatched commits remain", "count", len(result.Unmatched))
 	
-	return unmatched
+	return result.Unmatched
 }
 
 // CommitGroup represents a group of related commits
@@ -139,22 +51,44 @@ type CommitGroup struct {
 
 // GroupCommitsByMessage groups commits by their message prefix (e.g., "feat:", "fix:")
 func GroupCommitsByMessage(commits []git.Commit) []CommitGroup {
+	return groupCommits(commits, func(commit git.Commit) string {
+		return extractMessagePrefix(commit.Message)
+	})
+}
+
+// GroupCommitsByAuthor groups commits by author name
+func GroupCommitsByAuthor(commits []git.Commit) []CommitGroup {
+	return groupCommits(commits, func(commit git.Commit) string {
+		return commit.Author
+	})
+}
+
+// GroupCommitsByDate groups commits by commit date
+func GroupCommitsByDate(commits []git.Commit) []CommitGroup {
+	return groupCommits(commits, func(commit git.Commit) string {
+		return commit.Date
+	})
+}
+
+// groupCommits groups commits by key. Groups appear in order of their first commit
+// and commits keep their original order inside each group.
+func groupCommits(commits []git.Commit, key func(git.Commit) string) []CommitGroup {
 	groups := make(map[string][]git.Commit)
 	var order []string
 
 	for _, commit := range commits {
-		prefix := extractMessagePrefix(commit.Message)
-		if _, exists := groups[prefix]; !exists {
-			order = append(order, prefix)
+		k := key(commit)
+		if _, exists := groups[k]; !exists {
+			order = append(order, k)
 		}
-		groups[prefix] = append(groups[prefix], commit)
+		groups[k] = append(groups[k], commit)
 	}
 
 	var result []CommitGroup
-	for _, prefix := range order {
+	for _, k := range order {
 		result = append(result, CommitGroup{
-			Title:   prefix,
-			Commits: groups[prefix],
+			Title:   k,
+			Commits: groups[k],
 		})
 	}
 
@@ -163,14 +97,64 @@ func GroupCommitsByMessage(commits []git.Commit) []CommitGroup {
 
 // extractMessagePrefix extracts the conventional commit prefix (feat:, fix:, etc.)
 func extractMessagePrefix(message string) string {
-	firstLine := strings.Split(message, "\n")[0]
-	if strings.Contains(firstLine, ":") {
-		parts := strings.SplitN(firstLine, ":", 2)
-		return strings.TrimSpace(parts[0]) + ":"
+	if parsed := conventional.Parse(message); parsed.IsStandard {
+		return parsed.Type + ":"
 	}
 	return "other:"
 }
 
+// TypeFilter selects commits by Conventional Commits type and scope
+type TypeFilter struct {
+	Types        []string // Keep only these types (empty keeps all)
+	Scopes       []string // Keep only these scopes (empty keeps all)
+	ExcludeTypes []string // Drop these types
+}
+
+// IsEmpty reports whether the filter keeps every commit
+func (f TypeFilter) IsEmpty() bool {
+	return len(f.Types) == 0 && len(f.Scopes) == 0 && len(f.ExcludeTypes) == 0
+}
+
+// FilterCommitsByType filters commits by their parsed type and scope.
+// Commits that don't follow Conventional Commits have type "other".
+func FilterCommitsByType(commits []git.Commit, filter TypeFilter) []git.Commit {
+	if filter.IsEmpty() {
+		return commits
+	}
+
+	filtered := make([]git.Commit, 0)
+	for _, commit := range commits {
+		parsed := conventional.Parse(commit.FullMessage())
+		commitType := parsed.Type
+		if !parsed.IsStandard {
+			commitType = "other"
+		}
+
+		if len(filter.Types) > 0 && !containsFold(filter.Types, commitType) {
+			continue
+		}
+		if len(filter.Scopes) > 0 && !containsFold(filter.Scopes, parsed.Scope) {
+			continue
+		}
+		if containsFold(filter.ExcludeTypes, commitType) {
+			continue
+		}
+		filtered = append(filtered, commit)
+	}
+
+	return filtered
+}
+
+// containsFold reports whether values contains s, ignoring case
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), s) {
+			return true
+		}
+	}
+	return false
+}
+
 // CommitSummary provides a summary of commits for display
 type CommitSummary struct {
 	Total    int
@@ -195,3 +179,127 @@ func SummarizeCommits(commits []git.Commit) CommitSummary {
 
 	return summary
 }
+
+// ExtractTickets returns the ticket IDs referenced in a commit's subject or trailers
+func ExtractTickets(commit git.Commit, pattern *regexp.Regexp) []string {
+	parsed := conventional.Parse(commit.FullMessage())
+
+	texts := []string{commit.Message}
+	for _, footer := range parsed.Footers {
+		texts = append(texts, footer.Value)
+	}
+
+	seen := make(map[string]bool)
+	var tickets []string
+	for _, text := range texts {
+		for _, ticket := range pattern.FindAllString(text, -1) {
+			if !seen[ticket] {
+				seen[ticket] = true
+				tickets = append(tickets, ticket)
+			}
+		}
+	}
+	return tickets
+}
+
+// FilterCommitsByTicket keeps commits that reference any of the given tickets
+func FilterCommitsByTicket(commits []git.Commit, pattern *regexp.Regexp, tickets []string) []git.Commit {
+	filtered := make([]git.Commit, 0)
+	for _, commit := range commits {
+		for _, ticket := range ExtractTickets(commit, pattern) {
+			if containsFold(tickets, ticket) {
+				filtered = append(filtered, commit)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// Merge policies: how merge commits in the source branch are selected and picked
+const (
+	MergePolicySkip     = "skip"     // Drop merges; the commits they brought in stay selectable
+	MergePolicyMainline = "mainline" // Pick merges with -m; commits only reachable through them are hidden
+	MergePolicyExpand   = "expand"   // Replace each selected merge with the commits it brought in
+)
+
+// SkipMerges drops merge commits and returns how many were dropped
+func SkipMerges(commits []git.Commit) ([]git.Commit, int) {
+	filtered := make([]git.Commit, 0, len(commits))
+	for _, commit := range commits {
+		if !commit.IsMerge() {
+			filtered = append(filtered, commit)
+		}
+	}
+	return filtered, len(commits) - len(filtered)
+}
+
+// FilterFirstParent keeps the commits on the source branch's first-parent chain
+func FilterFirstParent(commits []git.Commit, firstParent map[string]bool) []git.Commit {
+	filtered := make([]git.Commit, 0, len(commits))
+	for _, commit := range commits {
+		if firstParent[commit.Hash] {
+			filtered = append(filtered, commit)
+		}
+	}
+	return filtered
+}
+
+// ExpandMerges replaces every merge in selected with the commits it brought in,
+// taken from all in their original order and tagged with the merge hash. merged
+// maps a merge hash to the hashes it brought in. Each commit appears once.
+func ExpandMerges(selected, all []git.Commit, merged map[string][]string) []git.Commit {
+	seen := make(map[string]bool)
+	expanded := make([]git.Commit, 0, len(selected))
+	for _, commit := range selected {
+		if !commit.IsMerge() {
+			if !seen[commit.Hash] {
+				seen[commit.Hash] = true
+				expanded = append(expanded, commit)
+			}
+			continue
+		}
+
+		brought := make(map[string]bool, len(merged[commit.Hash]))
+		for _, hash := range merged[commit.Hash] {
+			brought[hash] = true
+		}
+		for _, candidate := range all {
+			if !brought[candidate.Hash] || seen[candidate.Hash] {
+				continue
+			}
+			seen[candidate.Hash] = true
+			candidate.Via = commit.Hash
+			expanded = append(expanded, candidate)
+		}
+	}
+	return expanded
+}
+
+// ExcludeIgnored drops the commits marked with chr ignore
+func ExcludeIgnored(commits []git.Commit, set *marks.Set) []git.Commit {
+	if set.Len() == 0 {
+		return commits
+	}
+	filtered := make([]git.Commit, 0, len(commits))
+	for _, commit := range commits {
+		if m, ok := set.Lookup(commit.Hash); !ok || m.Kind != marks.KindIgnored {
+			filtered = append(filtered, commit)
+		}
+	}
+	return filtered
+}
+
+// ExcludeCommits drops the commits whose hash is in hashes
+func ExcludeCommits(commits []git.Commit, hashes map[string]bool) []git.Commit {
+	if len(hashes) == 0 {
+		return commits
+	}
+	filtered := make([]git.Commit, 0, len(commits))
+	for _, commit := range commits {
+		if !hashes[commit.Hash] {
+			filtered = append(filtered, commit)
+		}
+	}
+	return filtered
+}
@@ -1,9 +1,13 @@
 package picker
 
 import (
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/carlosarraes/chr/internal/git"
+	"github.com/carlosarraes/chr/internal/logging"
+	"github.com/carlosarraes/chr/internal/marks"
 )
 
 func TestCommitMatcher_FindMatches(t *testing.T) {
@@ -20,7 +24,7 @@ func TestCommitMatcher_FindMatches(t *testing.T) {
 		{Hash: "xyz222", Author: "Other User", Message: "chore: update dependencies", Date: "2024-01-02"},
 	}
 
-	matcher := NewCommitMatcher()
+	matcher := NewCommitMatcher(MatchOptions{})
 	matches := matcher.FindMatches(sourceCommits, targetCommits)
 
 	// Should find 1 match (the "feat: add new feature" commit)
@@ -51,7 +55,7 @@ func TestCommitMatcher_GetUnmatched(t *testing.T) {
 		{Hash: "xyz111", Author: "Test User", Message: "feat: add new feature", Date: "2024-01-01"}, // This matches
 	}
 
-	matcher := NewCommitMatcher()
+	matcher := NewCommitMatcher(MatchOptions{})
 	unmatched := matcher.GetUnmatched(sourceCommits, targetCommits)
 
 	// Should return 2 unmatched commits
@@ -150,7 +154,7 @@ func TestCommitMatcher_MatchingStrategies(t *testing.T) {
 		},
 	}
 
-	matcher := NewCommitMatcher()
+	matcher := NewCommitMatcher(MatchOptions{})
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -184,7 +188,7 @@ func TestFilterUnpickedCommits(t *testing.T) {
 		{Hash: "xyz222", Author: "Other User", Message: "chore: unrelated", Date: "2024-01-04"},
 	}
 
-	unpicked := FilterUnpickedCommits(prdCommits, hmlCommits, false)
+	unpicked := FilterUnpickedCommits(prdCommits, hmlCommits, MatchOptions{}, logging.Discard())
 
 	// Should return 2 commits that haven't been picked yet
 	if len(unpicked) != 2 {
@@ -212,10 +216,202 @@ func TestFilterUnpickedCommits_EmptyHML(t *testing.T) {
 
 	var hmlCommits []git.Commit // Empty HML branch
 
-	unpicked := FilterUnpickedCommits(prdCommits, hmlCommits, false)
+	unpicked := FilterUnpickedCommits(prdCommits, hmlCommits, MatchOptions{}, logging.Discard())
 
 	// Should return all PRD commits since HML is empty
 	if len(unpicked) != 2 {
 		t.Errorf("Expected 2 unpicked commits, got %d", len(unpicked))
 	}
 }
+
+func TestGroupCommits(t *testing.T) {
+	commits := []git.Commit{
+		{Hash: "a1", Author: "Alice", Message: "feat: add login", Date: "2024-01-03"},
+		{Hash: "b1", Author: "Bob", Message: "fix: typo", Date: "2024-01-03"},
+		{Hash: "a2", Author: "Alice", Message: "fix: crash", Date: "2024-01-02"},
+		{Hash: "a3", Author: "Alice", Message: "feat: add logout", Date: "2024-01-01"},
+	}
+
+	tests := []struct {
+		name     string
+		group    func([]git.Commit) []CommitGroup
+		expected map[string][]string
+		order    []string
+	}{
+		{
+			name:  "by message type",
+			group: GroupCommitsByMessage,
+			expected: map[string][]string{
+				"feat:": {"a1", "a3"},
+				"fix:":  {"b1", "a2"},
+			},
+			order: []string{"feat:", "fix:"},
+		},
+		{
+			name:  "by author",
+			group: GroupCommitsByAuthor,
+			expected: map[string][]string{
+				"Alice": {"a1", "a2", "a3"},
+				"Bob":   {"b1"},
+			},
+			order: []string{"Alice", "Bob"},
+		},
+		{
+			name:  "by date",
+			group: GroupCommitsByDate,
+			expected: map[string][]string{
+				"2024-01-03": {"a1", "b1"},
+				"2024-01-02": {"a2"},
+				"2024-01-01": {"a3"},
+			},
+			order: []string{"2024-01-03", "2024-01-02", "2024-01-01"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups := tt.group(commits)
+			if len(groups) != len(tt.order) {
+				t.Fatalf("Expected %d groups, got %d", len(tt.order), len(groups))
+			}
+			for i, group := range groups {
+				if group.Title != tt.order[i] {
+					t.Errorf("Expected group %d to be %q, got %q", i, tt.order[i], group.Title)
+				}
+				var hashes []string
+				for _, commit := range group.Commits {
+					hashes = append(hashes, commit.Hash)
+				}
+				if strings.Join(hashes, ",") != strings.Join(tt.expected[group.Title], ",") {
+					t.Errorf("Expected %q to contain %v in order, got %v", group.Title, tt.expected[group.Title], hashes)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractMessagePrefix(t *testing.T) {
+	tests := map[string]string{
+		"feat: add login":            "feat:",
+		"fix(api)!: drop v1":         "fix:",
+		"Merge: x":                   "other:",
+		"WIP http://example.com/a:b": "other:",
+		"update readme":              "other:",
+	}
+
+	for message, expected := range tests {
+		if got := extractMessagePrefix(message); got != expected {
+			t.Errorf("extractMessagePrefix(%q) = %q, expected %q", message, got, expected)
+		}
+	}
+}
+
+func TestFilterCommitsByType(t *testing.T) {
+	commits := []git.Commit{
+		{Hash: "a1", Message: "feat(api): add endpoint"},
+		{Hash: "a2", Message: "fix(api): handle nil"},
+		{Hash: "a3", Message: "fix(ui): align button"},
+		{Hash: "a4", Message: "chore: bump deps"},
+		{Hash: "a5", Message: "WIP something"},
+	}
+
+	tests := []struct {
+		name     string
+		filter   TypeFilter
+		expected []string
+	}{
+		{"empty filter keeps all", TypeFilter{}, []string{"a1", "a2", "a3", "a4", "a5"}},
+		{"by types", TypeFilter{Types: []string{"feat", "fix"}}, []string{"a1", "a2", "a3"}},
+		{"by scope", TypeFilter{Scopes: []string{"api"}}, []string{"a1", "a2"}},
+		{"types and scope", TypeFilter{Types: []string{"fix"}, Scopes: []string{"api"}}, []string{"a2"}},
+		{"exclude type", TypeFilter{ExcludeTypes: []string{"chore", "other"}}, []string{"a1", "a2", "a3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hashes []string
+			for _, commit := range FilterCommitsByType(commits, tt.filter) {
+				hashes = append(hashes, commit.Hash)
+			}
+			if strings.Join(hashes, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected %v, got %v", tt.expected, hashes)
+			}
+		})
+	}
+}
+
+func TestFilterCommitsByTicket(t *testing.T) {
+	pattern := regexp.MustCompile(`ZUP-[0-9]+`)
+	commits := []git.Commit{
+		{Hash: "a1", Message: "feat: ZUP-456 add invoices"},
+		{Hash: "a2", Message: "fix: rounding", Body: "Explain the fix.\n\nRefs: ZUP-789"},
+		{Hash: "a3", Message: "fix: ZUP-4567 unrelated"},
+		{Hash: "a4", Message: "chore: mentions nothing", Body: "Unlike ZUP-456 this is free text."},
+		{Hash: "a5", Message: "feat: no ticket"},
+	}
+
+	if tickets := ExtractTickets(commits[1], pattern); len(tickets) != 1 || tickets[0] != "ZUP-789" {
+		t.Errorf("Expected ZUP-789 from the Refs trailer, got %v", tickets)
+	}
+
+	var hashes []string
+	for _, commit := range FilterCommitsByTicket(commits, pattern, []string{"ZUP-456", "zup-789"}) {
+		hashes = append(hashes, commit.Hash)
+	}
+	if strings.Join(hashes, ",") != "a1,a2" {
+		t.Errorf("Expected a1,a2, got %v", hashes)
+	}
+}
+
+func TestMergePolicies(t *testing.T) {
+	// Newest first, as git log lists them: m1 merged f1 and f2 into the branch
+	all := []git.Commit{
+		{Hash: "d2", Parents: []string{"m1"}, Message: "fix: direct after merge"},
+		{Hash: "m1", Parents: []string{"d1", "f2"}, Message: "Merge branch 'feature'"},
+		{Hash: "f2", Parents: []string{"f1"}, Message: "feat: feature part 2"},
+		{Hash: "f1", Parents: []string{"d0"}, Message: "feat: feature part 1"},
+		{Hash: "d1", Parents: []string{"d0"}, Message: "fix: direct before merge"},
+	}
+	hashesOf := func(commits []git.Commit) string {
+		var hashes []string
+		for _, commit := range commits {
+			hashes = append(hashes, commit.Hash)
+		}
+		return strings.Join(hashes, ",")
+	}
+
+	skipped, count := SkipMerges(all)
+	if hashesOf(skipped) != "d2,f2,f1,d1" || count != 1 {
+		t.Errorf("SkipMerges: got %s (%d skipped)", hashesOf(skipped), count)
+	}
+
+	firstParent := map[string]bool{"d2": true, "m1": true, "d1": true}
+	if got := hashesOf(FilterFirstParent(all, firstParent)); got != "d2,m1,d1" {
+		t.Errorf("FilterFirstParent: got %s", got)
+	}
+
+	// Only the merge was selected (e.g. by author), its commits come along
+	expanded := ExpandMerges([]git.Commit{all[1], all[4]}, all, map[string][]string{"m1": {"f2", "f1"}})
+	if got := hashesOf(expanded); got != "f2,f1,d1" {
+		t.Errorf("ExpandMerges: got %s", got)
+	}
+	if expanded[0].Via != "m1" || expanded[2].Via != "" {
+		t.Errorf("Expected expanded commits to be tagged with their merge, got %+v", expanded)
+	}
+}
+
+func TestExcludeIgnored(t *testing.T) {
+	commits := []git.Commit{{Hash: "aaaaaaa"}, {Hash: "bbbbbbb"}, {Hash: "ccccccc"}}
+	set := marks.NewSet([]marks.Mark{
+		{Commit: "aaaaaaa111", Kind: marks.KindIgnored},
+		{Commit: "bbbbbbb222", Kind: marks.KindPicked},
+	})
+
+	got := ExcludeIgnored(commits, set)
+	if len(got) != 2 || got[0].Hash != "bbbbbbb" || got[1].Hash != "ccccccc" {
+		t.Errorf("Expected only the ignored commit to be dropped, got %+v", got)
+	}
+	if got := ExcludeIgnored(commits, nil); len(got) != 3 {
+		t.Errorf("Expected no marks to keep every commit, got %+v", got)
+	}
+}
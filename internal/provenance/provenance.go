@@ -0,0 +1,136 @@
+package provenance
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/carlosarraes/chr/internal/git"
+)
+
+// Ref is the notes ref chr records where each picked commit came from. Like any
+// notes ref it is shared by pushing and fetching it: git push origin refs/notes/chr
+const Ref = "refs/notes/chr"
+
+// Note records where a commit on the target branch was picked from
+type Note struct {
+	Source       string // Full hash of the source commit
+	SourceBranch string
+	Card         string
+	Session      string // ID of the pick session
+	User         string
+}
+
+// String renders the note as stored on the target commit
+func (n Note) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "picked-from: %s\n", n.Source)
+	fmt.Fprintf(&b, "source-branch: %s\n", n.SourceBranch)
+	fmt.Fprintf(&b, "card: %s\n", n.Card)
+	fmt.Fprintf(&b, "session: %s\n", n.Session)
+	fmt.Fprintf(&b, "by: %s\n", n.User)
+	return b.String()
+}
+
+// Parse reads notes back. Squashing picked commits concatenates their notes,
+// so a commit's note text may hold several.
+func Parse(text string) ([]Note, error) {
+	var notes []Note
+	for _, line := range strings.Split(text, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if key == "picked-from" {
+			notes = append(notes, Note{Source: value})
+			continue
+		}
+		if len(notes) == 0 {
+			continue
+		}
+		n := &notes[len(notes)-1]
+		switch key {
+		case "source-branch":
+			n.SourceBranch = value
+		case "card":
+			n.Card = value
+		case "session":
+			n.Session = value
+		case "by":
+			n.User = value
+		}
+	}
+
+	if len(notes) == 0 {
+		return nil, fmt.Errorf("not a chr provenance note")
+	}
+	return notes, nil
+}
+
+// Record attaches notes to target commits, by full hash. Commits that already
+// carry the same note are left alone, so a resumed pick only notes its new commits.
+// Ref is added to the repository's local notes.rewriteRef config when missing,
+// so git carries the notes over when the commits are amended, rebased or squashed.
+func Record(ctx context.Context, repoDir string, notes map[string]Note) error {
+	if len(notes) == 0 {
+		return nil
+	}
+	if err := git.EnsureConfigValue(ctx, repoDir, "notes.rewriteRef", Ref); err != nil {
+		return err
+	}
+	existing, err := git.ListNotes(ctx, repoDir, Ref)
+	if err != nil {
+		return err
+	}
+
+	targets := make([]string, 0, len(notes))
+	for target := range notes {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+	for _, target := range targets {
+		text := notes[target].String()
+		if existing[target] == text {
+			continue
+		}
+		if err := git.AddNote(ctx, repoDir, Ref, target, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load sets PickedFrom on the commits that carry provenance notes
+func Load(ctx context.Context, repoDir string, commits []git.Commit) error {
+	notes, err := git.ListNotes(ctx, repoDir, Ref)
+	if err != nil {
+		return err
+	}
+	if len(notes) == 0 {
+		return nil
+	}
+
+	// Notes are keyed by full hashes; commits carry abbreviated ones
+	full := make([]string, 0, len(notes))
+	for hash := range notes {
+		full = append(full, hash)
+	}
+	sort.Strings(full)
+	for i := range commits {
+		n := sort.SearchStrings(full, commits[i].Hash)
+		if n == len(full) || !strings.HasPrefix(full[n], commits[i].Hash) {
+			continue
+		}
+		parsed, err := Parse(notes[full[n]])
+		if err != nil {
+			continue
+		}
+		commits[i].PickedFrom = nil
+		for _, note := range parsed {
+			commits[i].PickedFrom = append(commits[i].PickedFrom, note.Source)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package provenance
+
+import (
+	"context"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/carlosarraes/chr/internal/git"
+	"github.com/carlosarraes/chr/internal/picker"
+)
+
+func TestParse_RoundTrip(t *testing.T) {
+	n := Note{
+		Source:       "1234567890abcdef1234567890abcdef12345678",
+		SourceBranch: "ZUP-123-prd",
+		Card:         "123",
+		Session:      "20240301-103000",
+		User:         "Ana",
+	}
+
+	got, err := Parse(n.String())
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, []Note{n}) {
+		t.Errorf("Expected %+v, got %+v", n, got)
+	}
+}
+
+func TestParse_Squashed(t *testing.T) {
+	first := Note{Source: "aaaa", Card: "1"}
+	second := Note{Source: "bbbb", Card: "2"}
+
+	// git concatenates notes with a blank line when commits are squashed
+	got, err := Parse(first.String() + "\n" + second.String())
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, []Note{first, second}) {
+		t.Errorf("Expected both notes, got %+v", got)
+	}
+
+	if _, err := Parse("Reviewed-by: Bob\n"); err == nil {
+		t.Error("Expected a note without picked-from to be rejected")
+	}
+}
+
+func gitOutput(t *testing.T, repoDir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func TestRecordAndLoad(t *testing.T) {
+	repoDir := t.TempDir()
+	gitOutput(t, repoDir, "init")
+	gitOutput(t, repoDir, "config", "user.name", "Test User")
+	gitOutput(t, repoDir, "config", "user.email", "test@example.com")
+	gitOutput(t, repoDir, "commit", "--allow-empty", "-m", "feat: picked")
+	target := gitOutput(t, repoDir, "rev-parse", "HEAD")
+	ctx := context.Background()
+
+	notes := map[string]Note{target: {Source: "abcdef1234", SourceBranch: "ZUP-1-prd", Card: "1", Session: "s1", User: "Ana"}}
+	if err := Record(ctx, repoDir, notes); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	before := gitOutput(t, repoDir, "rev-parse", Ref)
+	if err := Record(ctx, repoDir, notes); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if after := gitOutput(t, repoDir, "rev-parse", Ref); after != before {
+		t.Error("Expected recording the same notes again to leave the notes ref alone")
+	}
+	if got := gitOutput(t, repoDir, "config", "--get-all", "notes.rewriteRef"); got != Ref {
+		t.Errorf("Expected notes.rewriteRef %s, got %q", Ref, got)
+	}
+
+	commits := []git.Commit{{Hash: target[:7]}, {Hash: "0000000"}}
+	if err := Load(ctx, repoDir, commits); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !reflect.DeepEqual(commits[0].PickedFrom, []string{"abcdef1234"}) || commits[1].PickedFrom != nil {
+		t.Errorf("Expected only the noted commit to be picked from abcdef1234, got %+v", commits)
+	}
+}
+
+func TestLoad_AfterAmend(t *testing.T) {
+	repoDir := t.TempDir()
+	gitOutput(t, repoDir, "init")
+	gitOutput(t, repoDir, "config", "user.name", "Test User")
+	gitOutput(t, repoDir, "config", "user.email", "test@example.com")
+	gitOutput(t, repoDir, "commit", "--allow-empty", "-m", "feat: picked")
+	original := gitOutput(t, repoDir, "rev-parse", "HEAD")
+	ctx := context.Background()
+
+	source := "abcdef1234567890abcdef1234567890abcdef12"
+	if err := Record(ctx, repoDir, map[string]Note{original: {Source: source, Card: "1"}}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	// notes.rewriteRef copies the note, so both commits share its blob
+	gitOutput(t, repoDir, "commit", "--amend", "--allow-empty", "-m", "feat: picked and reworded")
+	amended := gitOutput(t, repoDir, "rev-parse", "HEAD")
+	if amended == original {
+		t.Fatal("Expected amending to change the commit")
+	}
+
+	targets := []git.Commit{{Hash: amended[:7], Author: "Test User", Message: "feat: picked and reworded"}}
+	if err := Load(ctx, repoDir, targets); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	sources := []git.Commit{{Hash: source[:7], Author: "Test User", Message: "feat: picked"}}
+	result := picker.NewCommitMatcher(picker.MatchOptions{}).Match(sources, targets)
+	if len(result.Matches) != 1 || result.Matches[0].Strategy != picker.StrategyNote {
+		t.Errorf("Expected the amended commit to match its source by note, got %+v", result)
+	}
+}
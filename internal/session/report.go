@@ -0,0 +1,131 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/carlosarraes/chr/internal/git"
+)
+
+// Report maps every commit selected in a pick session to its outcome on the target branch
+type Report struct {
+	Session      string        `json:"session"`
+	User         string        `json:"user"`
+	SourceBranch string        `json:"source_branch"`
+	TargetBranch string        `json:"target_branch"`
+	Outcome      string        `json:"outcome"`
+	Entries      []ReportEntry `json:"entries"`
+}
+
+// ReportEntry is one source commit and the target commit it produced, if any
+type ReportEntry struct {
+	Source  string         `json:"source"`
+	Target  string         `json:"target,omitempty"`
+	Status  git.PickStatus `json:"status"`
+	Author  string         `json:"author"`
+	Subject string         `json:"subject"`
+}
+
+// NewReport builds a report from a session's pick results, in pick order
+func NewReport(s Session) Report {
+	commits := make(map[string]git.Commit, len(s.Commits))
+	for _, commit := range s.Commits {
+		commits[commit.Hash] = commit
+	}
+
+	report := Report{
+		Session:      s.ID,
+		User:         s.User,
+		SourceBranch: s.SourceBranch,
+		TargetBranch: s.TargetBranch,
+		Outcome:      s.Outcome,
+		Entries:      make([]ReportEntry, 0, len(s.Picks)),
+	}
+	for _, pick := range s.Picks {
+		commit := commits[pick.Source]
+		report.Entries = append(report.Entries, ReportEntry{
+			Source:  pick.Source,
+			Target:  pick.NewHash,
+			Status:  pick.Status,
+			Author:  commit.Author,
+			Subject: commit.Message,
+		})
+	}
+	return report
+}
+
+// Count returns how many entries have the given status
+func (r Report) Count(status git.PickStatus) int {
+	n := 0
+	for _, entry := range r.Entries {
+		if entry.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Summary counts the entries per status, e.g. "3 picked, 1 already applied"
+func (r Report) Summary() string {
+	var parts []string
+	for _, status := range []git.PickStatus{git.PickStatusPicked, git.PickStatusAlreadyApplied, git.PickStatusConflict, git.PickStatusPending} {
+		if n := r.Count(status); n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, status.Label()))
+		}
+	}
+	if len(parts) == 0 {
+		return "nothing picked"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Markdown renders the report as a table suitable for PR descriptions
+func (r Report) Markdown() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## Cherry-pick %s → %s\n\n", r.SourceBranch, r.TargetBranch)
+	fmt.Fprintf(&b, "Session `%s` by %s: %s.\n\n", r.Session, r.User, r.Summary())
+
+	b.WriteString("| Source | Target | Status | Author | Subject |\n")
+	b.WriteString("|--------|--------|--------|--------|---------|\n")
+	for _, entry := range r.Entries {
+		target := "-"
+		if entry.Target != "" {
+			target = "`" + ShortHash(entry.Target) + "`"
+		}
+		subject := strings.ReplaceAll(entry.Subject, "|", "\\|")
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s |\n", entry.Source, target, entry.Status.Label(), entry.Author, subject)
+	}
+
+	return b.String()
+}
+
+// WriteReport writes the report to path, as JSON for .json files and markdown otherwise
+func WriteReport(path string, r Report) error {
+	var data []byte
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		encoded, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+		data = append(encoded, '\n')
+	} else {
+		data = []byte(r.Markdown())
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// ShortHash abbreviates a full commit hash for display
+func ShortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
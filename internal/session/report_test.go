@@ -0,0 +1,97 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/carlosarraes/chr/internal/git"
+)
+
+func testReportSession() Session {
+	return Session{
+		ID:           "20240115-103000",
+		User:         "Test User",
+		SourceBranch: "ZUP-123-prd",
+		TargetBranch: "ZUP-123-hml",
+		Commits: []git.Commit{
+			{Hash: "ccc333", Author: "Bob", Message: "fix: handle a|b"},
+			{Hash: "bbb222", Author: "Alice", Message: "chore: bump"},
+			{Hash: "aaa111", Author: "Alice", Message: "feat: add feature"},
+		},
+		Picks: []git.PickResult{
+			{Source: "aaa111", NewHash: "1234567890abcdef", Status: git.PickStatusPicked},
+			{Source: "bbb222", Status: git.PickStatusAlreadyApplied},
+			{Source: "ccc333", Status: git.PickStatusConflict},
+		},
+		Outcome: OutcomeConflict,
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	report := NewReport(testReportSession())
+
+	if len(report.Entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(report.Entries))
+	}
+	first := report.Entries[0]
+	if first.Source != "aaa111" || first.Target != "1234567890abcdef" || first.Author != "Alice" || first.Subject != "feat: add feature" {
+		t.Errorf("Unexpected first entry: %+v", first)
+	}
+	if got := report.Summary(); got != "1 picked, 1 already applied, 1 conflict" {
+		t.Errorf("Unexpected summary: %q", got)
+	}
+	if report.Count(git.PickStatusPicked) != 1 || report.Count(git.PickStatusPending) != 0 {
+		t.Errorf("Unexpected counts: %d picked, %d pending", report.Count(git.PickStatusPicked), report.Count(git.PickStatusPending))
+	}
+}
+
+func TestReport_Markdown(t *testing.T) {
+	md := NewReport(testReportSession()).Markdown()
+
+	for _, want := range []string{
+		"## Cherry-pick ZUP-123-prd → ZUP-123-hml",
+		"| `aaa111` | `1234567` | picked | Alice | feat: add feature |",
+		"| `bbb222` | - | already applied | Alice | chore: bump |",
+		"fix: handle a\\|b",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Expected markdown to contain %q, got:\n%s", want, md)
+		}
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	dir := t.TempDir()
+	report := NewReport(testReportSession())
+
+	jsonPath := filepath.Join(dir, "report.json")
+	if err := WriteReport(jsonPath, report); err != nil {
+		t.Fatalf("WriteReport failed: %v", err)
+	}
+	data, err := os.ReadFile(jsonPath)
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Report is not valid JSON: %v", err)
+	}
+	if len(decoded.Entries) != 3 || decoded.Entries[1].Status != git.PickStatusAlreadyApplied {
+		t.Errorf("Unexpected decoded report: %+v", decoded)
+	}
+
+	mdPath := filepath.Join(dir, "report.md")
+	if err := WriteReport(mdPath, report); err != nil {
+		t.Fatalf("WriteReport failed: %v", err)
+	}
+	data, err = os.ReadFile(mdPath)
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "## Cherry-pick") {
+		t.Errorf("Expected markdown report, got:\n%s", data)
+	}
+}
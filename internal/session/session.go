@@ -0,0 +1,150 @@
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/carlosarraes/chr/internal/git"
+)
+
+const (
+	OutcomeSuccess     = "success"
+	OutcomeConflict    = "conflict"
+	OutcomeStopped     = "stopped"     // Stopped on an already-applied commit (empty policy "stop")
+	OutcomeInterrupted = "interrupted" // Cancelled between two commits, e.g. by Ctrl-C
+	OutcomeFailed      = "failed"      // A commit could not be picked, e.g. on a git error
+	OutcomeAborted     = "aborted"     // Undone with chr pick --abort
+)
+
+// Session records a single chr pick run
+type Session struct {
+	ID           string           `json:"id"`
+	StartedAt    time.Time        `json:"started_at"`
+	User         string           `json:"user"`
+	Card         string           `json:"card"`
+	SourceBranch string           `json:"source_branch"`
+	TargetBranch string           `json:"target_branch"`
+	PickBranch   string           `json:"pick_branch,omitempty"`
+	StartHead    string           `json:"start_head,omitempty"` // Commit the branch was on before the first pick
+	Commits      []git.Commit     `json:"commits"`
+	Picks        []git.PickResult `json:"picks,omitempty"`
+	Mainline     int              `json:"mainline,omitempty"`
+	EmptyPolicy  string           `json:"empty_policy,omitempty"`
+	Outcome      string           `json:"outcome"`
+
+	// What to do once the pick finishes, kept for chr pick --continue
+	Push           bool   `json:"push,omitempty"`
+	ForceWithLease bool   `json:"force_with_lease,omitempty"`
+	Report         string `json:"report,omitempty"` // Absolute path of the --report file
+	JiraComment    bool   `json:"jira_comment,omitempty"`
+}
+
+// Resumable reports whether the session stopped midway and can still be
+// continued or aborted
+func (s Session) Resumable() bool {
+	switch s.Outcome {
+	case OutcomeConflict, OutcomeStopped, OutcomeInterrupted, OutcomeFailed:
+		return true
+	}
+	return false
+}
+
+// NewID creates a session ID from the session start time
+func NewID(startedAt time.Time) string {
+	return startedAt.Format("20060102-150405")
+}
+
+// GetLogPath returns the session log location inside a repository's .git directory
+func GetLogPath(gitDir string) string {
+	return filepath.Join(gitDir, "chr", "sessions.jsonl")
+}
+
+// Append adds a session to the repository's session log. A session resumed
+// with chr pick --continue is appended again under the same ID; the latest entry wins.
+func Append(gitDir string, s Session) error {
+	logPath := GetLogPath(gitDir)
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open session log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write session log: %w", err)
+	}
+	return nil
+}
+
+// LoadAll returns every recorded session, oldest first
+func LoadAll(gitDir string) ([]Session, error) {
+	f, err := os.Open(GetLogPath(gitDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session log: %w", err)
+	}
+	defer f.Close()
+
+	var sessions []Session
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var s Session
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+			continue // Skip corrupt entries instead of hiding the whole history
+		}
+		sessions = append(sessions, s)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read session log: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// Last returns the most recent session, or nil when none was recorded
+func Last(gitDir string) (*Session, error) {
+	sessions, err := LoadAll(gitDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return nil, nil
+	}
+	return &sessions[len(sessions)-1], nil
+}
+
+// AlreadyApplied returns the source commits earlier sessions from sourceBranch to
+// targetBranch found already applied on the target. They count as picked even
+// though no matching commit exists on the target.
+func AlreadyApplied(sessions []Session, sourceBranch, targetBranch string) map[string]bool {
+	applied := make(map[string]bool)
+	for _, s := range sessions {
+		if s.SourceBranch != sourceBranch || s.TargetBranch != targetBranch {
+			continue
+		}
+		for _, pick := range s.Picks {
+			if pick.Status == git.PickStatusAlreadyApplied {
+				applied[pick.Source] = true
+			}
+		}
+	}
+	return applied
+}
@@ -0,0 +1,149 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/carlosarraes/chr/internal/git"
+)
+
+func TestLast_NoSessions(t *testing.T) {
+	last, err := Last(t.TempDir())
+	if err != nil {
+		t.Fatalf("Last failed: %v", err)
+	}
+	if last != nil {
+		t.Errorf("Expected no session, got %+v", last)
+	}
+}
+
+func TestAppendAndLoad(t *testing.T) {
+	gitDir := t.TempDir()
+	startedAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	first := Session{
+		ID:           NewID(startedAt),
+		StartedAt:    startedAt,
+		User:         "Test User",
+		Card:         "123",
+		SourceBranch: "ZUP-123-prd",
+		TargetBranch: "ZUP-123-hml",
+		Commits: []git.Commit{
+			{Hash: "abc123", Author: "Test User", Message: "feat: add feature", Date: "2024-01-15"},
+		},
+		Outcome: OutcomeSuccess,
+	}
+	second := first
+	second.ID = NewID(startedAt.Add(time.Hour))
+	second.Outcome = OutcomeConflict
+
+	if err := Append(gitDir, first); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	if err := Append(gitDir, second); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	sessions, err := LoadAll(gitDir)
+	if err != nil {
+		t.Fatalf("LoadAll failed: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("Expected 2 sessions, got %d", len(sessions))
+	}
+	if sessions[0].ID != "20240115-103000" {
+		t.Errorf("Expected ID 20240115-103000, got %s", sessions[0].ID)
+	}
+	if len(sessions[0].Commits) != 1 || sessions[0].Commits[0].Hash != "abc123" {
+		t.Errorf("Expected commits to round-trip, got %+v", sessions[0].Commits)
+	}
+
+	last, err := Last(gitDir)
+	if err != nil {
+		t.Fatalf("Last failed: %v", err)
+	}
+	if last == nil || last.Outcome != OutcomeConflict {
+		t.Errorf("Expected last session to be the conflict one, got %+v", last)
+	}
+}
+
+func TestLoadAll_SkipsCorruptLines(t *testing.T) {
+	gitDir := t.TempDir()
+	if err := Append(gitDir, Session{ID: "good"}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	f, err := os.OpenFile(GetLogPath(gitDir), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open log: %v", err)
+	}
+	f.WriteString("{not json\n")
+	f.Close()
+
+	sessions, err := LoadAll(gitDir)
+	if err != nil {
+		t.Fatalf("LoadAll failed: %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Errorf("Expected 1 valid session, got %d", len(sessions))
+	}
+}
+
+func TestLoadAll_AcceptsEmptyStatus(t *testing.T) {
+	gitDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Dir(GetLogPath(gitDir)), 0755); err != nil {
+		t.Fatalf("Failed to create log dir: %v", err)
+	}
+	line := `{"id":"old","picks":[{"source":"aaa111","status":"empty"}]}` + "\n"
+	if err := os.WriteFile(GetLogPath(gitDir), []byte(line), 0644); err != nil {
+		t.Fatalf("Failed to write log: %v", err)
+	}
+
+	sessions, err := LoadAll(gitDir)
+	if err != nil {
+		t.Fatalf("LoadAll failed: %v", err)
+	}
+	if len(sessions) != 1 || len(sessions[0].Picks) != 1 || sessions[0].Picks[0].Status != git.PickStatusAlreadyApplied {
+		t.Errorf("Expected the empty status to load as already_applied, got %+v", sessions)
+	}
+}
+
+func TestAlreadyApplied(t *testing.T) {
+	sessions := []Session{
+		{
+			SourceBranch: "ZUP-123-prd",
+			TargetBranch: "ZUP-123-hml",
+			Picks: []git.PickResult{
+				{Source: "aaa111", Status: git.PickStatusAlreadyApplied},
+				{Source: "bbb222", NewHash: "fff999", Status: git.PickStatusPicked},
+			},
+		},
+		{
+			SourceBranch: "ZUP-123-hml",
+			TargetBranch: "ZUP-123-prd",
+			Picks:        []git.PickResult{{Source: "ccc333", Status: git.PickStatusAlreadyApplied}},
+		},
+	}
+
+	applied := AlreadyApplied(sessions, "ZUP-123-prd", "ZUP-123-hml")
+	if len(applied) != 1 || !applied["aaa111"] {
+		t.Errorf("Expected only aaa111, got %v", applied)
+	}
+}
+
+func TestResumable(t *testing.T) {
+	for outcome, want := range map[string]bool{
+		OutcomeSuccess:     false,
+		OutcomeConflict:    true,
+		OutcomeStopped:     true,
+		OutcomeInterrupted: true,
+		OutcomeFailed:      true,
+		OutcomeAborted:     false,
+	} {
+		if got := (Session{Outcome: outcome}).Resumable(); got != want {
+			t.Errorf("Resumable() for %s = %v, want %v", outcome, got, want)
+		}
+	}
+}
@@ -2,16 +2,27 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/carlosarraes/chr/cmd"
+	"github.com/carlosarraes/chr/internal/picker"
 )
 
 func main() {
-	ctx := context.Background()
-	if err := cmd.Execute(ctx); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	// Ctrl-C and SIGTERM cancel ctx: a pick finishes the commit it is on and stops
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := cmd.Execute(ctx)
+	stop()
+
+	if err != nil {
+		// Nothing to pick was already reported as a message, not a failure
+		if !errors.Is(err, picker.ErrNothingToPick) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		os.Exit(cmd.ExitCode(err))
 	}
 }